@@ -0,0 +1,9 @@
+data "debug_command" "example" {
+  command = ["aws", "sts", "get-caller-identity"]
+}
+
+data "debug_command" "which" {
+  command         = ["sh", "-c", "command -v terraform git curl"]
+  fail_on_nonzero = false
+  timeout         = "10s"
+}
@@ -0,0 +1,4 @@
+# Panic while the data source is read during the plan.
+data "debug_crash" "example" {
+  mode = "panic"
+}
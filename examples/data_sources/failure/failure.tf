@@ -1 +1,9 @@
 data "debug_failure" "example" {}
+
+data "debug_system_info" "this" {}
+
+# Fail the plan when the agent has less than 4 GiB of memory.
+data "debug_failure" "memory" {
+  condition = data.debug_system_info.this.memory_total < 4 * 1024 * 1024 * 1024
+  message   = "The agent has less than 4 GiB of memory."
+}
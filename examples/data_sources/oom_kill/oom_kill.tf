@@ -19,3 +19,13 @@ data "debug_oom_kill" "example" {
   memory     = 2 * 1024 * 1024 * 1024 # 2GB
   block_size = 100 * 1024 * 1024      # 100MB
 }
+
+# Grow virtual memory by 512MB every 32 seconds without committing it.
+data "debug_oom_kill" "overcommit" {
+  memory          = 8 * 1024 * 1024 * 1024 # 8GB
+  block_size      = 100 * 1024 * 1024      # 100MB
+  allocation_rate = 16 * 1024 * 1024       # 16MB/s
+  touch           = "none"
+  ramp            = "step"
+  step_duration   = "32s"
+}
@@ -19,3 +19,56 @@ data "debug_plan_artifact" "example" {
   file_name = "plan-artifact-example"
   file_size = 1024 # 1 MB
 }
+
+# Roughly half of this artifact compresses away when the working directory is
+# packaged for upload.
+data "debug_plan_artifact" "compressible" {
+  file_name          = "plan-artifact-compressible"
+  file_size          = 1048576
+  content_mode       = "compressible_ratio"
+  compressible_ratio = 0.5
+}
+
+# Many small files in a deep tree, with links to exercise archive edge cases.
+data "debug_plan_artifact" "tree" {
+  file_name       = "plan-artifact-tree"
+  file_count      = 10000
+  directory_depth = 20
+  name_length     = 32
+  total_size      = 10485760
+  symlinks        = true
+}
+
+# A 10 GB artifact that takes no time or disk space to create.
+data "debug_plan_artifact" "sparse" {
+  file_name  = "plan-artifact-sparse"
+  file_size  = 10737418240
+  allocation = "sparse"
+}
+
+# Reproducible random content, so the id is the same on every run.
+data "debug_plan_artifact" "seeded" {
+  file_name = "plan-artifact-seeded"
+  file_size = 1048576
+  seed      = 42
+}
+
+# Inside .terraform, which is packaged differently from the rest of the
+# working directory.
+data "debug_plan_artifact" "data_dir" {
+  file_name = "plan-artifact-data-dir"
+  file_size = 1048576
+  directory = "artifacts"
+  location  = "data_dir"
+}
+
+# Next to the module, creating the directory if needed.
+data "debug_plan_artifact" "module" {
+  file_name = "plan-artifact-module"
+  file_size = 1048576
+  directory = "${path.module}/artifacts"
+}
+
+output "data_dir_artifact_path" {
+  value = data.debug_plan_artifact.data_dir.absolute_path
+}
@@ -1,3 +1,19 @@
 resource "debug_command" "example" {
   create_command = ["ls", "-l"]
 }
+
+resource "debug_command" "lifecycle" {
+  create_command  = ["sh", "-c", "mktemp -d"]
+  read_command    = ["sh", "-c", "test -d \"$DEBUG_COMMAND_STDOUT\" && echo \"$DEBUG_COMMAND_STDOUT\""]
+  update_command  = ["sh", "-c", "touch \"$DEBUG_COMMAND_STDOUT\" && echo \"$DEBUG_COMMAND_STDOUT\""]
+  destroy_command = ["sh", "-c", "rm -rf \"$DEBUG_COMMAND_STDOUT\""]
+}
+
+resource "debug_command" "script" {
+  interpreter = "bash"
+  script      = <<-EOT
+    set -euo pipefail
+    echo "user: $(id -un)"
+    echo "terraform: $(command -v terraform || echo 'not found')"
+  EOT
+}
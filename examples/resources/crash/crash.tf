@@ -0,0 +1,6 @@
+# Exit with code 3 while the resource is being updated.
+resource "debug_crash" "example" {
+  mode      = "exit"
+  exit_code = 3
+  phase     = "update"
+}
@@ -0,0 +1,2 @@
+# Command resources can be imported by their create command, as a JSON array.
+terraform import debug_command.example '["echo", "hello"]'
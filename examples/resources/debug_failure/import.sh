@@ -0,0 +1,2 @@
+# Failure resources can be imported by their ID.
+terraform import debug_failure.example my-failure-id
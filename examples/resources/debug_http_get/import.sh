@@ -0,0 +1,2 @@
+# HTTP GET resources can be imported by URL. The request is performed on import.
+terraform import debug_http_get.example https://example.com
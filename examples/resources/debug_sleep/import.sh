@@ -0,0 +1,2 @@
+# Sleep resources can be imported by their ID, a timestamp in RFC3339 format.
+terraform import debug_sleep.example 2025-01-01T00:00:00Z
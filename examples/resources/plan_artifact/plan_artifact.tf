@@ -0,0 +1,6 @@
+# Removed again on destroy, so it does not affect later runs.
+resource "debug_plan_artifact" "example" {
+  file_name   = "plan-artifact-example"
+  file_size   = 1048576
+  on_existing = "regenerate_if_different"
+}
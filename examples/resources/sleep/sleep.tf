@@ -3,3 +3,10 @@ resource "debug_sleep" "examples" {
   update_duration  = "5s"
   destroy_duration = "3s"
 }
+
+# Sleep for 10 to 20 minutes, logging the remaining time every minute.
+resource "debug_sleep" "long" {
+  duration           = "15m"
+  jitter             = "5m"
+  heartbeat_interval = "1m"
+}
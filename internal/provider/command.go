@@ -0,0 +1,570 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// defaultMaxOutputBytes is the default cap on the captured stdout and stderr
+// of a command.
+const defaultMaxOutputBytes = 1024 * 1024 // 1 MiB
+
+// Default levels at which command output is streamed to the Terraform logs.
+const (
+	defaultStdoutLogLevel = "info"
+	defaultStderrLogLevel = "warn"
+)
+
+// commandLogLevels are the accepted values for stdout_log_level and
+// stderr_log_level.
+var commandLogLevels = []string{"trace", "debug", "info", "warn", "error", "off"}
+
+// defaultInterpreter is the interpreter used to run scripts when none is
+// configured.
+const defaultInterpreter = "/bin/sh"
+
+// commandKillSignals maps the accepted values of kill_signal to signals.
+var commandKillSignals = map[string]syscall.Signal{
+	"SIGHUP":  syscall.SIGHUP,
+	"SIGINT":  syscall.SIGINT,
+	"SIGQUIT": syscall.SIGQUIT,
+	"SIGKILL": syscall.SIGKILL,
+	"SIGTERM": syscall.SIGTERM,
+}
+
+// commandKillSignalNames are the accepted values for kill_signal.
+var commandKillSignalNames = []string{"SIGTERM", "SIGINT", "SIGHUP", "SIGQUIT", "SIGKILL"}
+
+// defaultKillSignal is the signal sent to a timed out or cancelled command
+// when none is configured.
+const defaultKillSignal = "SIGTERM"
+
+// commandModel holds the attributes shared by the command resource and data
+// source.
+type commandModel struct {
+	AllowedExitCodes           types.List    `tfsdk:"allowed_exit_codes"`
+	FailOnNonzero              types.Bool    `tfsdk:"fail_on_nonzero"`
+	Environment                types.Map     `tfsdk:"environment"`
+	InheritEnvironment         types.Bool    `tfsdk:"inherit_environment"`
+	WorkingDir                 types.String  `tfsdk:"working_dir"`
+	Stdin                      types.String  `tfsdk:"stdin"`
+	StdinBase64                types.String  `tfsdk:"stdin_base64"`
+	Timeout                    types.String  `tfsdk:"timeout"`
+	GracePeriod                types.String  `tfsdk:"grace_period"`
+	KillSignal                 types.String  `tfsdk:"kill_signal"`
+	MaxOutputBytes             types.Int64   `tfsdk:"max_output_bytes"`
+	StdoutLogLevel             types.String  `tfsdk:"stdout_log_level"`
+	StderrLogLevel             types.String  `tfsdk:"stderr_log_level"`
+	Stderr                     types.String  `tfsdk:"stderr"`
+	Stdout                     types.String  `tfsdk:"stdout"`
+	ExitCode                   types.Int32   `tfsdk:"exit_code"`
+	Signaled                   types.Bool    `tfsdk:"signaled"`
+	Duration                   types.String  `tfsdk:"duration"`
+	Truncated                  types.Bool    `tfsdk:"truncated"`
+	OrphansKilled              types.Bool    `tfsdk:"orphans_killed"`
+	UserCPUTime                types.String  `tfsdk:"user_cpu_time"`
+	SystemCPUTime              types.String  `tfsdk:"system_cpu_time"`
+	MaxRSSBytes                types.Int64   `tfsdk:"max_rss_bytes"`
+	VoluntaryContextSwitches   types.Int64   `tfsdk:"voluntary_context_switches"`
+	InvoluntaryContextSwitches types.Int64   `tfsdk:"involuntary_context_switches"`
+	OutputFormat               types.String  `tfsdk:"output_format"`
+	OutputJSON                 types.Dynamic `tfsdk:"output_json"`
+	OutputMap                  types.Map     `tfsdk:"output_map"`
+	OutputLines                types.List    `tfsdk:"output_lines"`
+}
+
+// commandOptions describes how a command is run.
+type commandOptions struct {
+	Args []string
+	// Script, if set, is written to a temporary file whose path is appended
+	// to Args.
+	Script         string
+	Env            []string
+	Dir            string
+	Stdin          []byte
+	Timeout        time.Duration
+	GracePeriod    time.Duration
+	KillSignal     syscall.Signal
+	MaxOutputBytes int64
+	StdoutLogLevel string
+	StderrLogLevel string
+}
+
+// commandResult holds the captured output of a command run.
+type commandResult struct {
+	Stdout    string
+	Stderr    string
+	ExitCode  int
+	Signaled  bool
+	TimedOut  bool
+	Truncated bool
+	Duration  time.Duration
+	// OrphansKilled is set when processes spawned by a timed out or cancelled
+	// command were still running after it exited and had to be killed.
+	OrphansKilled bool
+	// UserTime and SystemTime are the CPU time consumed by the command.
+	UserTime   time.Duration
+	SystemTime time.Duration
+	// Usage is nil when resource usage is not available on the platform.
+	Usage *processUsage
+}
+
+// processUsage holds the platform-specific resource usage of a command.
+type processUsage struct {
+	MaxRSS                     int64
+	VoluntaryContextSwitches   int64
+	InvoluntaryContextSwitches int64
+}
+
+// commandOptions builds the options for running cmd from the configuration.
+// extraEnv is appended to the environment of the command.
+func (m *commandModel) commandOptions(ctx context.Context, cmd []string, extraEnv ...string) (*commandOptions, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	opts := &commandOptions{
+		Args: cmd,
+		Dir:  m.WorkingDir.ValueString(),
+		// Use an empty, non-nil environment so the command does not inherit the provider's.
+		Env:            []string{},
+		GracePeriod:    5 * time.Second,
+		KillSignal:     commandKillSignals[defaultKillSignal],
+		MaxOutputBytes: defaultMaxOutputBytes,
+		StdoutLogLevel: defaultStdoutLogLevel,
+		StderrLogLevel: defaultStderrLogLevel,
+	}
+
+	if m.InheritEnvironment.IsNull() || m.InheritEnvironment.ValueBool() {
+		opts.Env = os.Environ()
+	}
+
+	if !m.Environment.IsNull() && !m.Environment.IsUnknown() {
+		env := make(map[string]string, len(m.Environment.Elements()))
+		diags.Append(m.Environment.ElementsAs(ctx, &env, false)...)
+
+		if diags.HasError() {
+			return nil, diags
+		}
+
+		for k, v := range env {
+			opts.Env = append(opts.Env, k+"="+v)
+		}
+	}
+
+	opts.Env = append(opts.Env, extraEnv...)
+
+	if !m.Stdin.IsNull() {
+		opts.Stdin = []byte(m.Stdin.ValueString())
+	}
+
+	if !m.StdinBase64.IsNull() {
+		stdin, err := base64.StdEncoding.DecodeString(m.StdinBase64.ValueString())
+		if err != nil {
+			diags.AddAttributeError(
+				path.Root("stdin_base64"),
+				"Invalid Standard Input",
+				"Could not decode stdin_base64: "+err.Error(),
+			)
+			return nil, diags
+		}
+		opts.Stdin = stdin
+	}
+
+	if !m.Timeout.IsNull() {
+		timeout, err := time.ParseDuration(m.Timeout.ValueString())
+		if err != nil {
+			diags.AddAttributeError(
+				path.Root("timeout"),
+				"Invalid Timeout",
+				"Could not parse timeout: "+err.Error(),
+			)
+			return nil, diags
+		}
+		opts.Timeout = timeout
+	}
+
+	if !m.GracePeriod.IsNull() {
+		gracePeriod, err := time.ParseDuration(m.GracePeriod.ValueString())
+		if err != nil {
+			diags.AddAttributeError(
+				path.Root("grace_period"),
+				"Invalid Grace Period",
+				"Could not parse grace_period: "+err.Error(),
+			)
+			return nil, diags
+		}
+		opts.GracePeriod = gracePeriod
+	}
+
+	if !m.KillSignal.IsNull() {
+		opts.KillSignal = commandKillSignals[m.KillSignal.ValueString()]
+	}
+
+	if !m.MaxOutputBytes.IsNull() {
+		opts.MaxOutputBytes = m.MaxOutputBytes.ValueInt64()
+	}
+
+	if !m.StdoutLogLevel.IsNull() {
+		opts.StdoutLogLevel = m.StdoutLogLevel.ValueString()
+	}
+
+	if !m.StderrLogLevel.IsNull() {
+		opts.StderrLogLevel = m.StderrLogLevel.ValueString()
+	}
+
+	return opts, diags
+}
+
+func (m *commandModel) setResult(result *commandResult) {
+	m.Stderr = types.StringValue(result.Stderr)
+	m.Stdout = types.StringValue(result.Stdout)
+	m.ExitCode = types.Int32Value(int32(result.ExitCode))
+	m.Signaled = types.BoolValue(result.Signaled)
+	m.Duration = types.StringValue(result.Duration.String())
+	m.Truncated = types.BoolValue(result.Truncated)
+	m.OrphansKilled = types.BoolValue(result.OrphansKilled)
+	m.UserCPUTime = types.StringValue(result.UserTime.String())
+	m.SystemCPUTime = types.StringValue(result.SystemTime.String())
+
+	m.MaxRSSBytes = types.Int64Null()
+	m.VoluntaryContextSwitches = types.Int64Null()
+	m.InvoluntaryContextSwitches = types.Int64Null()
+	if result.Usage != nil {
+		m.MaxRSSBytes = types.Int64Value(result.Usage.MaxRSS)
+		m.VoluntaryContextSwitches = types.Int64Value(result.Usage.VoluntaryContextSwitches)
+		m.InvoluntaryContextSwitches = types.Int64Value(result.Usage.InvoluntaryContextSwitches)
+	}
+}
+
+// keepResult sets the attributes that are set from the result of a command
+// run to their values in prior, for updates that run no command. The plan
+// leaves them unknown when they were never set, for example after an import.
+func (m *commandModel) keepResult(prior *commandModel) {
+	m.Stderr = prior.Stderr
+	m.Stdout = prior.Stdout
+	m.ExitCode = prior.ExitCode
+	m.Signaled = prior.Signaled
+	m.Duration = prior.Duration
+	m.Truncated = prior.Truncated
+	m.OrphansKilled = prior.OrphansKilled
+	m.UserCPUTime = prior.UserCPUTime
+	m.SystemCPUTime = prior.SystemCPUTime
+	m.MaxRSSBytes = prior.MaxRSSBytes
+	m.VoluntaryContextSwitches = prior.VoluntaryContextSwitches
+	m.InvoluntaryContextSwitches = prior.InvoluntaryContextSwitches
+}
+
+// failed reports whether the result should fail the operation, based on
+// allowed_exit_codes and fail_on_nonzero.
+func (m *commandModel) failed(ctx context.Context, result *commandResult) (bool, diag.Diagnostics) {
+	if !m.FailOnNonzero.IsNull() && !m.FailOnNonzero.ValueBool() {
+		return false, nil
+	}
+
+	allowed := []int32{0}
+	if !m.AllowedExitCodes.IsNull() && !m.AllowedExitCodes.IsUnknown() {
+		allowed = nil
+		if diags := m.AllowedExitCodes.ElementsAs(ctx, &allowed, false); diags.HasError() {
+			return false, diags
+		}
+	}
+
+	return result.Signaled || result.TimedOut || !slices.Contains(allowed, int32(result.ExitCode)), nil
+}
+
+// failureDetail describes a failed command run for use in a diagnostic.
+func (r *commandResult) failureDetail(name string) string {
+	status := fmt.Sprintf("exited with code %d", r.ExitCode)
+	if r.Signaled {
+		status = "was terminated by a signal"
+	}
+	if r.TimedOut {
+		status = "timed out and " + status
+	}
+
+	return fmt.Sprintf("The %s %s after %s.\n\nstdout:\n%s\n\nstderr:\n%s",
+		name, status, r.Duration, r.Stdout, r.Stderr)
+}
+
+// warnings returns the warnings to report for a command run.
+func (r *commandResult) warnings(name string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if r.OrphansKilled {
+		diags.AddWarning(
+			"Orphaned Processes Killed",
+			fmt.Sprintf("The %s was stopped, but processes it started were still running and had to be killed.", name),
+		)
+	}
+
+	return diags
+}
+
+// commandArgs converts a list of strings from the configuration into an argv slice.
+func commandArgs(ctx context.Context, list types.List) ([]string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	parts := make([]types.String, 0, len(list.Elements()))
+	diags.Append(list.ElementsAs(ctx, &parts, false)...)
+
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	var cmd []string
+	for _, part := range parts {
+		cmd = append(cmd, part.ValueString())
+	}
+
+	return cmd, diags
+}
+
+// commandID returns the ID of a command, the SHA256 hash of its arguments
+// joined with NUL. When a script is set, its SHA256 hash is included as the
+// last argument.
+func commandID(cmd []string, script string) string {
+	if script != "" {
+		scriptHash := sha256.Sum256([]byte(script))
+		cmd = append(slices.Clone(cmd), hex.EncodeToString(scriptHash[:]))
+	}
+
+	hash := sha256.Sum256([]byte(strings.Join(cmd, "\x00")))
+	return hex.EncodeToString(hash[:])
+}
+
+// writeScript writes script to a temporary file and returns its path along
+// with a function that removes it.
+func writeScript(script string) (string, func(), error) {
+	fh, err := os.CreateTemp("", "terraform-provider-debug-script-*")
+	if err != nil {
+		return "", nil, err
+	}
+
+	cleanup := func() { os.Remove(fh.Name()) }
+
+	if _, err := fh.WriteString(script); err != nil {
+		fh.Close()
+		cleanup()
+		return "", nil, err
+	}
+
+	if err := fh.Close(); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+
+	return fh.Name(), cleanup, nil
+}
+
+// runCommand executes the command and captures its output. A non-zero exit
+// code or a timeout is reported in the result rather than as an error, which
+// is only returned when the command could not be run.
+func runCommand(ctx context.Context, opts *commandOptions) (*commandResult, error) {
+	if len(opts.Args) == 0 {
+		return nil, fmt.Errorf("command must not be empty")
+	}
+
+	args := opts.Args
+	if opts.Script != "" {
+		scriptPath, cleanup, err := writeScript(opts.Script)
+		if err != nil {
+			return nil, fmt.Errorf("unable to write script: %w", err)
+		}
+		defer cleanup()
+
+		args = append(slices.Clone(args), scriptPath)
+	}
+
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	command := exec.CommandContext(ctx, args[0], args[1:]...)
+	command.Env = opts.Env
+	command.Dir = opts.Dir
+
+	if opts.Stdin != nil {
+		command.Stdin = bytes.NewReader(opts.Stdin)
+	}
+
+	// Run the command in its own process group and, when the context is done,
+	// ask the whole group to terminate, so that processes spawned by shell
+	// wrappers are stopped too. The command is killed if it is still running
+	// after the grace period.
+	setProcessGroup(command)
+	command.Cancel = func() error {
+		if err := signalProcessGroup(command.Process, opts.KillSignal); err != nil {
+			return command.Process.Kill()
+		}
+		return nil
+	}
+	command.WaitDelay = opts.GracePeriod
+
+	// Capture the output and stream it to the Terraform logs line by line, so
+	// that the progress of long-running commands is visible while they run.
+	stderr := &limitedBuffer{limit: opts.MaxOutputBytes}
+	stdout := &limitedBuffer{limit: opts.MaxOutputBytes}
+	stderrLog := &lineLogger{ctx: ctx, stream: "stderr", level: opts.StderrLogLevel}
+	stdoutLog := &lineLogger{ctx: ctx, stream: "stdout", level: opts.StdoutLogLevel}
+	command.Stderr = io.MultiWriter(stderr, stderrLog)
+	command.Stdout = io.MultiWriter(stdout, stdoutLog)
+
+	tflog.Debug(ctx, "Running command", map[string]interface{}{
+		"command": args,
+		"dir":     opts.Dir,
+		"timeout": opts.Timeout.String(),
+	})
+
+	start := time.Now()
+	err := command.Run()
+	duration := time.Since(start)
+
+	stderrLog.Flush()
+	stdoutLog.Flush()
+
+	// The command could not be started.
+	if command.ProcessState == nil {
+		return nil, err
+	}
+
+	var exitErr *exec.ExitError
+	if err != nil && !errors.As(err, &exitErr) && !errors.Is(err, exec.ErrWaitDelay) && ctx.Err() == nil {
+		return nil, err
+	}
+
+	// Processes that outlived a timed out or cancelled command would keep
+	// running on the machine, so kill them.
+	var orphansKilled bool
+	if ctx.Err() != nil {
+		orphansKilled = killProcessGroup(command.Process)
+	}
+
+	exitCode := command.ProcessState.ExitCode()
+
+	return &commandResult{
+		Stdout:   stdout.String(),
+		Stderr:   stderr.String(),
+		ExitCode: exitCode,
+		// ExitCode is -1 for processes that were terminated by a signal.
+		Signaled:      exitCode == -1,
+		TimedOut:      errors.Is(ctx.Err(), context.DeadlineExceeded),
+		Truncated:     stdout.truncated || stderr.truncated,
+		Duration:      duration,
+		OrphansKilled: orphansKilled,
+		UserTime:      command.ProcessState.UserTime(),
+		SystemTime:    command.ProcessState.SystemTime(),
+		Usage:         resourceUsage(command.ProcessState),
+	}, nil
+}
+
+// limitedBuffer is an io.Writer that keeps at most limit bytes and discards
+// the rest, so that a chatty command cannot exhaust memory or bloat state.
+// A limit of zero or less disables the cap.
+type limitedBuffer struct {
+	buf       bytes.Buffer
+	limit     int64
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if b.limit <= 0 {
+		return b.buf.Write(p)
+	}
+
+	if remaining := b.limit - int64(b.buf.Len()); int64(len(p)) > remaining {
+		b.truncated = true
+		b.buf.Write(p[:max(remaining, 0)])
+		return len(p), nil
+	}
+
+	return b.buf.Write(p)
+}
+
+func (b *limitedBuffer) String() string {
+	return b.buf.String()
+}
+
+// maxLogLineBytes is the length after which a line without a newline is
+// logged anyway.
+const maxLogLineBytes = 64 * 1024 // 64 KiB
+
+// lineLogger is an io.Writer that logs each line written to it through tflog
+// at the configured level.
+type lineLogger struct {
+	ctx    context.Context
+	stream string
+	level  string
+	buf    []byte
+}
+
+func (l *lineLogger) Write(p []byte) (int, error) {
+	if l.level == "off" {
+		return len(p), nil
+	}
+
+	l.buf = append(l.buf, p...)
+	for {
+		i := bytes.IndexByte(l.buf, '\n')
+		if i < 0 {
+			break
+		}
+
+		l.log(l.buf[:i])
+		l.buf = l.buf[i+1:]
+	}
+
+	if len(l.buf) >= maxLogLineBytes {
+		l.Flush()
+	}
+
+	return len(p), nil
+}
+
+// Flush logs any buffered partial line.
+func (l *lineLogger) Flush() {
+	if len(l.buf) > 0 {
+		l.log(l.buf)
+		l.buf = nil
+	}
+}
+
+func (l *lineLogger) log(line []byte) {
+	msg := strings.TrimSuffix(string(line), "\r")
+	fields := map[string]interface{}{
+		"stream": l.stream,
+	}
+
+	switch l.level {
+	case "trace":
+		tflog.Trace(l.ctx, msg, fields)
+	case "debug":
+		tflog.Debug(l.ctx, msg, fields)
+	case "info":
+		tflog.Info(l.ctx, msg, fields)
+	case "warn":
+		tflog.Warn(l.ctx, msg, fields)
+	case "error":
+		tflog.Error(l.ctx, msg, fields)
+	}
+}
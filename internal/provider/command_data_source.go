@@ -0,0 +1,322 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ datasource.DataSource = &CommandDataSource{}
+
+func NewCommandDataSource() datasource.DataSource {
+	return &CommandDataSource{}
+}
+
+type CommandDataSource struct {
+	providerData *debugProviderData
+}
+
+type CommandDataSourceModel struct {
+	Id          types.String `tfsdk:"id"`
+	Command     types.List   `tfsdk:"command"`
+	Script      types.String `tfsdk:"script"`
+	Interpreter types.String `tfsdk:"interpreter"`
+	commandModel
+}
+
+func (d *CommandDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_command"
+}
+
+func (d *CommandDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Executes a command when the data source is read and captures its output. " +
+			"Useful for inspecting the run environment during the plan phase, such as the binaries on the PATH or the credentials in use.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "SHA256 hash of the command and, if set, the script.",
+				Computed:            true,
+			},
+			"command": schema.ListAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Command to run. Must be a valid command with arguments. Exactly one of `command` or `script` must be set.",
+				Optional:            true,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+				},
+			},
+			"script": schema.StringAttribute{
+				MarkdownDescription: "Script to run with `interpreter`, instead of `command`. " +
+					"The script is written to a temporary file that is removed once it has run.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.ExactlyOneOf(path.MatchRoot("command")),
+				},
+			},
+			"interpreter": schema.StringAttribute{
+				MarkdownDescription: "Interpreter used to run `script`, such as `/bin/sh`, `bash` or `python3`. " +
+					"Names without a path are looked up on the PATH. Defaults to `/bin/sh`.",
+				Optional: true,
+			},
+			"allowed_exit_codes": schema.ListAttribute{
+				ElementType:         types.Int32Type,
+				MarkdownDescription: "Exit codes that are considered successful. Defaults to `[0]`.",
+				Optional:            true,
+			},
+			"fail_on_nonzero": schema.BoolAttribute{
+				MarkdownDescription: "Whether a command exiting with a code not listed in `allowed_exit_codes`, or terminated by a signal, fails the read. " +
+					"When `false`, the exit code and output are returned instead. Defaults to `true`.",
+				Optional: true,
+			},
+			"environment": schema.MapAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Environment variables to set for the command.",
+				Optional:            true,
+			},
+			"inherit_environment": schema.BoolAttribute{
+				MarkdownDescription: "Whether the command inherits the environment of the provider process. Defaults to `true`.",
+				Optional:            true,
+			},
+			"working_dir": schema.StringAttribute{
+				MarkdownDescription: "Working directory of the command. Defaults to the working directory of the provider process.",
+				Optional:            true,
+			},
+			"stdin": schema.StringAttribute{
+				MarkdownDescription: "Data to pass to the standard input of the command.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("stdin_base64")),
+				},
+			},
+			"stdin_base64": schema.StringAttribute{
+				MarkdownDescription: "Base64-encoded data to pass to the standard input of the command, for binary input.",
+				Optional:            true,
+			},
+			"timeout": schema.StringAttribute{
+				MarkdownDescription: "Maximum time the command may run, e.g. '30s'. When exceeded, the command and the processes it started are sent " +
+					"`kill_signal` and, if the command is still running after `grace_period`, SIGKILL.",
+				Optional: true,
+				Validators: []validator.String{
+					positiveDuration(),
+				},
+			},
+			"grace_period": schema.StringAttribute{
+				MarkdownDescription: "Time to wait after sending `kill_signal` to a timed out or cancelled command before killing it. Defaults to '5s'.",
+				Optional:            true,
+				Validators: []validator.String{
+					positiveDuration(),
+				},
+			},
+			"kill_signal": schema.StringAttribute{
+				MarkdownDescription: "Signal sent to a timed out or cancelled command and the processes it started. Commands run in their own " +
+					"process group, so shell wrappers do not leave processes behind. One of `SIGTERM`, `SIGINT`, `SIGHUP`, `SIGQUIT` or `SIGKILL`. " +
+					"Defaults to `SIGTERM`. Only the command itself is signalled on Windows.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(commandKillSignalNames...),
+				},
+			},
+			"max_output_bytes": schema.Int64Attribute{
+				MarkdownDescription: "Maximum number of bytes of stdout and stderr, each, to keep. Output beyond the limit is discarded " +
+					"and `truncated` is set. Set to `0` to disable the limit. Defaults to 1048576 (1 MiB).",
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"stdout_log_level": schema.StringAttribute{
+				MarkdownDescription: "Level at which each line of stdout is logged to the Terraform logs as it is written. " +
+					"One of `trace`, `debug`, `info`, `warn`, `error` or `off`. Defaults to `info`.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(commandLogLevels...),
+				},
+			},
+			"stderr_log_level": schema.StringAttribute{
+				MarkdownDescription: "Level at which each line of stderr is logged to the Terraform logs as it is written. " +
+					"One of `trace`, `debug`, `info`, `warn`, `error` or `off`. Defaults to `warn`.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(commandLogLevels...),
+				},
+			},
+			"output_format": schema.StringAttribute{
+				MarkdownDescription: "Format used to parse stdout into `output_json` (`json`), `output_map` (`dotenv`, KEY=VALUE lines) " +
+					"or `output_lines` (`lines`). One of `raw`, `json`, `dotenv` or `lines`. Defaults to `raw`, which does not parse stdout.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(outputFormats...),
+				},
+			},
+			"stderr": schema.StringAttribute{
+				MarkdownDescription: "Standard error output from the command.",
+				Computed:            true,
+			},
+			"stdout": schema.StringAttribute{
+				MarkdownDescription: "Standard output from the command.",
+				Computed:            true,
+			},
+			"exit_code": schema.Int32Attribute{
+				MarkdownDescription: "Exit code from the command, or `-1` if it was terminated by a signal.",
+				Computed:            true,
+			},
+			"signaled": schema.BoolAttribute{
+				MarkdownDescription: "Whether the command was terminated by a signal.",
+				Computed:            true,
+			},
+			"duration": schema.StringAttribute{
+				MarkdownDescription: "Wall-clock time the command took to run.",
+				Computed:            true,
+			},
+			"truncated": schema.BoolAttribute{
+				MarkdownDescription: "Whether stdout or stderr was truncated to `max_output_bytes`.",
+				Computed:            true,
+			},
+			"orphans_killed": schema.BoolAttribute{
+				MarkdownDescription: "Whether processes started by a timed out or cancelled command were still running after it exited and had to be killed.",
+				Computed:            true,
+			},
+			"user_cpu_time": schema.StringAttribute{
+				MarkdownDescription: "User CPU time consumed by the command.",
+				Computed:            true,
+			},
+			"system_cpu_time": schema.StringAttribute{
+				MarkdownDescription: "System CPU time consumed by the command.",
+				Computed:            true,
+			},
+			"max_rss_bytes": schema.Int64Attribute{
+				MarkdownDescription: "Maximum resident set size of the command in bytes. Not available on Windows.",
+				Computed:            true,
+			},
+			"voluntary_context_switches": schema.Int64Attribute{
+				MarkdownDescription: "Number of voluntary context switches of the command, such as when waiting for I/O. Not available on Windows.",
+				Computed:            true,
+			},
+			"involuntary_context_switches": schema.Int64Attribute{
+				MarkdownDescription: "Number of involuntary context switches of the command, such as when preempted by the scheduler. Not available on Windows.",
+				Computed:            true,
+			},
+			"output_json": schema.DynamicAttribute{
+				MarkdownDescription: "Stdout decoded as JSON when `output_format` is `json`.",
+				Computed:            true,
+			},
+			"output_map": schema.MapAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "KEY=VALUE pairs parsed from stdout when `output_format` is `dotenv`.",
+				Computed:            true,
+			},
+			"output_lines": schema.ListAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Lines of stdout when `output_format` is `lines`.",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *CommandDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// The provider has not been configured yet.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*debugProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *debugProviderData, got: %T.", req.ProviderData),
+		)
+		return
+	}
+
+	d.providerData = providerData
+}
+
+func (d *CommandDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data CommandDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	resp.Diagnostics.Append(d.providerData.checkMaxDuration(ctx, req.Config, "timeout", "grace_period")...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var (
+		cmd    []string
+		script string
+		diags  diag.Diagnostics
+	)
+
+	if !data.Script.IsNull() {
+		interpreter := defaultInterpreter
+		if !data.Interpreter.IsNull() {
+			interpreter = data.Interpreter.ValueString()
+		}
+
+		cmd = []string{interpreter}
+		script = data.Script.ValueString()
+	} else {
+		cmd, diags = commandArgs(ctx, data.Command)
+		if diags.HasError() {
+			resp.Diagnostics.Append(diags...)
+			return
+		}
+	}
+
+	data.Id = types.StringValue(commandID(cmd, script))
+
+	opts, diags := data.commandOptions(ctx, cmd)
+	if diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+	opts.Script = script
+
+	result, err := runCommand(ctx, opts)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Command Failed",
+			"An error occurred while executing the command: "+err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(result.warnings("command")...)
+
+	data.setResult(result)
+
+	failed, diags := data.failed(ctx, result)
+	if diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	if failed {
+		resp.Diagnostics.AddError(
+			"Command Failed",
+			result.failureDetail("command"),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(data.parseOutput(ctx)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
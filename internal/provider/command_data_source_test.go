@@ -0,0 +1,196 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestAccCommandDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCommandDataSourceConfig,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.debug_command.test",
+						tfjsonpath.New("stdout"),
+						knownvalue.StringExact("hello"),
+					),
+					statecheck.ExpectKnownValue(
+						"data.debug_command.test",
+						tfjsonpath.New("exit_code"),
+						knownvalue.Int32Exact(1),
+					),
+					statecheck.ExpectKnownValue(
+						"data.debug_command.test",
+						tfjsonpath.New("truncated"),
+						knownvalue.Bool(true),
+					),
+				},
+			},
+		},
+	})
+}
+
+const testAccCommandDataSourceConfig = `
+data "debug_command" "test" {
+  command            = ["sh", "-c", "echo hello world; exit 1"]
+  allowed_exit_codes = [0, 1]
+  max_output_bytes   = 5
+}
+`
+
+func TestAccCommandDataSource_script(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCommandDataSourceScriptConfig,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.debug_command.test",
+						tfjsonpath.New("stdout"),
+						knownvalue.StringExact("one\ntwo\n"),
+					),
+				},
+			},
+		},
+	})
+}
+
+const testAccCommandDataSourceScriptConfig = `
+data "debug_command" "test" {
+  script = <<-EOT
+    set -e
+    echo one
+    echo two
+  EOT
+}
+`
+
+func TestAccCommandDataSource_outputFormat(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCommandDataSourceOutputFormatConfig,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.debug_command.json",
+						tfjsonpath.New("output_json").AtMapKey("list").AtSliceIndex(1),
+						knownvalue.Int64Exact(2),
+					),
+					statecheck.ExpectKnownValue(
+						"data.debug_command.json",
+						tfjsonpath.New("output_json").AtMapKey("name"),
+						knownvalue.StringExact("debug"),
+					),
+					statecheck.ExpectKnownValue(
+						"data.debug_command.dotenv",
+						tfjsonpath.New("output_map"),
+						knownvalue.MapExact(map[string]knownvalue.Check{
+							"FOO": knownvalue.StringExact("bar"),
+							"BAZ": knownvalue.StringExact("qux quux"),
+						}),
+					),
+					statecheck.ExpectKnownValue(
+						"data.debug_command.lines",
+						tfjsonpath.New("output_lines"),
+						knownvalue.ListExact([]knownvalue.Check{
+							knownvalue.StringExact("a"),
+							knownvalue.StringExact("b"),
+						}),
+					),
+				},
+			},
+		},
+	})
+}
+
+const testAccCommandDataSourceOutputFormatConfig = `
+data "debug_command" "json" {
+  command       = ["echo", "{\"name\": \"debug\", \"list\": [1, 2], \"none\": null}"]
+  output_format = "json"
+}
+
+data "debug_command" "dotenv" {
+  command       = ["printf", "# comment\nFOO=bar\nexport BAZ=\"qux quux\"\n"]
+  output_format = "dotenv"
+}
+
+data "debug_command" "lines" {
+  command       = ["printf", "a\nb\n"]
+  output_format = "lines"
+}
+`
+
+func TestAccCommandDataSource_resourceUsage(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+data "debug_command" "test" {
+  command = ["true"]
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.debug_command.test",
+						tfjsonpath.New("user_cpu_time"),
+						knownvalue.NotNull(),
+					),
+					statecheck.ExpectKnownValue(
+						"data.debug_command.test",
+						tfjsonpath.New("max_rss_bytes"),
+						knownvalue.NotNull(),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestAccCommandDataSource_orphansKilled(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// The background sleep ignores SIGTERM and outlives the shell.
+				Config: `
+data "debug_command" "test" {
+  command         = ["sh", "-c", "(trap '' TERM; sleep 30) & wait"]
+  timeout         = "1s"
+  grace_period    = "1s"
+  fail_on_nonzero = false
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.debug_command.test",
+						tfjsonpath.New("signaled"),
+						knownvalue.Bool(true),
+					),
+					statecheck.ExpectKnownValue(
+						"data.debug_command.test",
+						tfjsonpath.New("orphans_killed"),
+						knownvalue.Bool(true),
+					),
+				},
+			},
+		},
+	})
+}
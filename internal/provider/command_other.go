@@ -0,0 +1,31 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build !unix
+
+package provider
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// setProcessGroup is not supported on this platform, so only the command
+// itself is signalled.
+func setProcessGroup(command *exec.Cmd) {}
+
+// signalProcessGroup sends sig to process only.
+func signalProcessGroup(process *os.Process, sig syscall.Signal) error {
+	return process.Signal(sig)
+}
+
+// killProcessGroup is not supported on this platform.
+func killProcessGroup(process *os.Process) bool {
+	return false
+}
+
+// resourceUsage is not supported on this platform.
+func resourceUsage(state *os.ProcessState) *processUsage {
+	return nil
+}
@@ -0,0 +1,196 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Formats accepted by output_format.
+const (
+	outputFormatRaw    = "raw"
+	outputFormatJSON   = "json"
+	outputFormatDotenv = "dotenv"
+	outputFormatLines  = "lines"
+)
+
+var outputFormats = []string{outputFormatRaw, outputFormatJSON, outputFormatDotenv, outputFormatLines}
+
+// parseOutput fills output_json, output_map and output_lines from stdout
+// according to output_format. Outputs that do not apply to the format are
+// set to null.
+func (m *commandModel) parseOutput(ctx context.Context) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	m.OutputJSON = types.DynamicNull()
+	m.OutputMap = types.MapNull(types.StringType)
+	m.OutputLines = types.ListNull(types.StringType)
+
+	// There is no output to parse until a command has run.
+	if m.Stdout.IsNull() {
+		return diags
+	}
+
+	stdout := m.Stdout.ValueString()
+
+	switch m.OutputFormat.ValueString() {
+	case outputFormatJSON:
+		value, err := parseJSONOutput(ctx, stdout)
+		if err != nil {
+			diags.AddAttributeError(
+				path.Root("stdout"),
+				"Invalid JSON Output",
+				"Could not parse stdout as JSON: "+err.Error(),
+			)
+			return diags
+		}
+		m.OutputJSON = types.DynamicValue(value)
+	case outputFormatDotenv:
+		env, err := parseDotenvOutput(stdout)
+		if err != nil {
+			diags.AddAttributeError(
+				path.Root("stdout"),
+				"Invalid Dotenv Output",
+				"Could not parse stdout as KEY=VALUE pairs: "+err.Error(),
+			)
+			return diags
+		}
+
+		outputMap, d := types.MapValueFrom(ctx, types.StringType, env)
+		diags.Append(d...)
+		m.OutputMap = outputMap
+	case outputFormatLines:
+		outputLines, d := types.ListValueFrom(ctx, types.StringType, parseLinesOutput(stdout))
+		diags.Append(d...)
+		m.OutputLines = outputLines
+	}
+
+	return diags
+}
+
+// parseJSONOutput decodes a JSON document into a Terraform value. Objects are
+// converted to objects, arrays to tuples and JSON nulls to null strings.
+func parseJSONOutput(ctx context.Context, s string) (attr.Value, error) {
+	dec := json.NewDecoder(strings.NewReader(s))
+	dec.UseNumber()
+
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("unexpected data after the JSON document")
+	}
+
+	return jsonToValue(ctx, v)
+}
+
+func jsonToValue(ctx context.Context, v interface{}) (attr.Value, error) {
+	switch v := v.(type) {
+	case nil:
+		return types.StringNull(), nil
+	case bool:
+		return types.BoolValue(v), nil
+	case string:
+		return types.StringValue(v), nil
+	case json.Number:
+		f, _, err := big.ParseFloat(v.String(), 10, 512, big.ToNearestEven)
+		if err != nil {
+			return nil, err
+		}
+		return types.NumberValue(f), nil
+	case []interface{}:
+		elemTypes := make([]attr.Type, 0, len(v))
+		elems := make([]attr.Value, 0, len(v))
+		for _, e := range v {
+			elem, err := jsonToValue(ctx, e)
+			if err != nil {
+				return nil, err
+			}
+			elemTypes = append(elemTypes, elem.Type(ctx))
+			elems = append(elems, elem)
+		}
+
+		tuple, diags := types.TupleValue(elemTypes, elems)
+		if diags.HasError() {
+			return nil, fmt.Errorf("unable to convert array: %v", diags)
+		}
+		return tuple, nil
+	case map[string]interface{}:
+		attrTypes := make(map[string]attr.Type, len(v))
+		attrs := make(map[string]attr.Value, len(v))
+		for k, e := range v {
+			elem, err := jsonToValue(ctx, e)
+			if err != nil {
+				return nil, err
+			}
+			attrTypes[k] = elem.Type(ctx)
+			attrs[k] = elem
+		}
+
+		object, diags := types.ObjectValue(attrTypes, attrs)
+		if diags.HasError() {
+			return nil, fmt.Errorf("unable to convert object: %v", diags)
+		}
+		return object, nil
+	default:
+		return nil, fmt.Errorf("unsupported JSON value of type %T", v)
+	}
+}
+
+// parseDotenvOutput parses KEY=VALUE lines. Blank lines and lines starting
+// with # are ignored, an optional "export " prefix is removed and values may
+// be wrapped in single or double quotes.
+func parseDotenvOutput(s string) (map[string]string, error) {
+	env := make(map[string]string)
+
+	for i, line := range parseLinesOutput(s) {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		line = strings.TrimPrefix(line, "export ")
+		k, v, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d is not in KEY=VALUE format", i+1)
+		}
+
+		k = strings.TrimSpace(k)
+		v = strings.TrimSpace(v)
+		if len(v) >= 2 && (v[0] == '"' || v[0] == '\'') && v[len(v)-1] == v[0] {
+			v = v[1 : len(v)-1]
+		}
+
+		env[k] = v
+	}
+
+	return env, nil
+}
+
+// parseLinesOutput splits s into lines, without line terminators. A trailing
+// newline does not produce an empty last line.
+func parseLinesOutput(s string) []string {
+	if s == "" {
+		return []string{}
+	}
+
+	lines := strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSuffix(line, "\r")
+	}
+
+	return lines
+}
@@ -5,37 +5,81 @@ package provider
 
 import (
 	"context"
-	"crypto/sha256"
-	"encoding/hex"
-	"os/exec"
-	"strings"
+	"encoding/json"
+	"fmt"
+	"strconv"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/dynamicplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int32planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 var _ resource.Resource = &CommandResource{}
+var _ resource.ResourceWithModifyPlan = &CommandResource{}
+var _ resource.ResourceWithImportState = &CommandResource{}
+
+// commandResultUnknowns holds the unknown values of the attributes that are
+// set from the result of a command run.
+var commandResultUnknowns = map[string]attr.Value{
+	"stdout":                       types.StringUnknown(),
+	"stderr":                       types.StringUnknown(),
+	"exit_code":                    types.Int32Unknown(),
+	"signaled":                     types.BoolUnknown(),
+	"duration":                     types.StringUnknown(),
+	"truncated":                    types.BoolUnknown(),
+	"orphans_killed":               types.BoolUnknown(),
+	"user_cpu_time":                types.StringUnknown(),
+	"system_cpu_time":              types.StringUnknown(),
+	"max_rss_bytes":                types.Int64Unknown(),
+	"voluntary_context_switches":   types.Int64Unknown(),
+	"involuntary_context_switches": types.Int64Unknown(),
+}
+
+// parsedOutputUnknowns holds the unknown values of the attributes that are
+// parsed from stdout.
+var parsedOutputUnknowns = map[string]attr.Value{
+	"output_json":  types.DynamicUnknown(),
+	"output_map":   types.MapUnknown(types.StringType),
+	"output_lines": types.ListUnknown(types.StringType),
+}
 
 func NewCommandResource() resource.Resource {
 	return &CommandResource{}
 }
 
 type CommandResource struct {
+	providerData *debugProviderData
 }
 
 type CommandResourceModel struct {
-	Id            types.String `tfsdk:"id"`
-	CreateCommand types.List   `tfsdk:"create_command"`
-	Stderr        types.String `tfsdk:"stderr"`
-	Stdout        types.String `tfsdk:"stdout"`
-	ExitCode      types.Int32  `tfsdk:"exit_code"`
+	Id             types.String `tfsdk:"id"`
+	CreateCommand  types.List   `tfsdk:"create_command"`
+	Script         types.String `tfsdk:"script"`
+	Interpreter    types.String `tfsdk:"interpreter"`
+	ReadCommand    types.List   `tfsdk:"read_command"`
+	UpdateCommand  types.List   `tfsdk:"update_command"`
+	DestroyCommand types.List   `tfsdk:"destroy_command"`
+	commandModel
 }
 
 func (r *CommandResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -44,7 +88,10 @@ func (r *CommandResource) Metadata(ctx context.Context, req resource.MetadataReq
 
 func (r *CommandResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		MarkdownDescription: "Command resource that executes a command and captures its output.",
+		MarkdownDescription: "Command resource that executes a command and captures its output. " +
+			"Optional read, update and destroy commands are run at the matching lifecycle points with the outputs of the prior state " +
+			"exposed through the `DEBUG_COMMAND_ID`, `DEBUG_COMMAND_STDOUT`, `DEBUG_COMMAND_STDERR` and `DEBUG_COMMAND_EXIT_CODE` environment variables. " +
+			"Can be imported with the create command as a JSON array, in which case the command is not run and the outputs are null and exposed as empty strings.",
 
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
@@ -55,9 +102,10 @@ func (r *CommandResource) Schema(ctx context.Context, req resource.SchemaRequest
 				},
 			},
 			"create_command": schema.ListAttribute{
-				ElementType:         types.StringType,
-				MarkdownDescription: "Command to be run during the Create operation. Must be a valid command with arguments.",
-				Optional:            true,
+				ElementType: types.StringType,
+				MarkdownDescription: "Command to be run during the Create operation. Must be a valid command with arguments. " +
+					"Exactly one of `create_command` or `script` must be set.",
+				Optional: true,
 				Validators: []validator.List{
 					listvalidator.SizeAtLeast(1),
 				},
@@ -65,6 +113,160 @@ func (r *CommandResource) Schema(ctx context.Context, req resource.SchemaRequest
 					listplanmodifier.RequiresReplace(),
 				},
 			},
+			"script": schema.StringAttribute{
+				MarkdownDescription: "Script to be run by `interpreter` during the Create operation, instead of `create_command`. " +
+					"The script is written to a temporary file that is removed once it has run.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.ExactlyOneOf(path.MatchRoot("create_command")),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"interpreter": schema.StringAttribute{
+				MarkdownDescription: "Interpreter used to run `script`, such as `/bin/sh`, `bash` or `python3`. " +
+					"Names without a path are looked up on the PATH. Defaults to `/bin/sh`.",
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(defaultInterpreter),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"read_command": schema.ListAttribute{
+				ElementType: types.StringType,
+				MarkdownDescription: "Command to be run during the Read operation. Its output replaces `stdout`, `stderr` and `exit_code`, " +
+					"so changes are reported as drift. A failing read command removes the resource from state so that it is recreated.",
+				Optional: true,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+				},
+			},
+			"update_command": schema.ListAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Command to be run during the Update operation. Its output replaces `stdout`, `stderr` and `exit_code`.",
+				Optional:            true,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+				},
+			},
+			"destroy_command": schema.ListAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Command to be run during the Delete operation.",
+				Optional:            true,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+				},
+			},
+			"allowed_exit_codes": schema.ListAttribute{
+				ElementType:         types.Int32Type,
+				MarkdownDescription: "Exit codes that are considered successful. Defaults to `[0]`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             listdefault.StaticValue(types.ListValueMust(types.Int32Type, []attr.Value{types.Int32Value(0)})),
+			},
+			"fail_on_nonzero": schema.BoolAttribute{
+				MarkdownDescription: "Whether a command exiting with a code not listed in `allowed_exit_codes`, or terminated by a signal, fails the operation. " +
+					"When `false`, the exit code and output are stored in state instead. Defaults to `true`.",
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(true),
+			},
+			"environment": schema.MapAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Environment variables to set for the commands.",
+				Optional:            true,
+			},
+			"inherit_environment": schema.BoolAttribute{
+				MarkdownDescription: "Whether the commands inherit the environment of the provider process. Defaults to `true`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(true),
+			},
+			"working_dir": schema.StringAttribute{
+				MarkdownDescription: "Working directory of the commands. Defaults to the working directory of the provider process.",
+				Optional:            true,
+			},
+			"stdin": schema.StringAttribute{
+				MarkdownDescription: "Data to pass to the standard input of the commands.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("stdin_base64")),
+				},
+			},
+			"stdin_base64": schema.StringAttribute{
+				MarkdownDescription: "Base64-encoded data to pass to the standard input of the commands, for binary input.",
+				Optional:            true,
+			},
+			"timeout": schema.StringAttribute{
+				MarkdownDescription: "Maximum time each command may run, e.g. '30s'. When exceeded, the command and the processes it started are sent " +
+					"`kill_signal` and, if the command is still running after `grace_period`, SIGKILL.",
+				Optional: true,
+				Validators: []validator.String{
+					positiveDuration(),
+				},
+			},
+			"grace_period": schema.StringAttribute{
+				MarkdownDescription: "Time to wait after sending `kill_signal` to a timed out or cancelled command before killing it. Defaults to '5s'.",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("5s"),
+				Validators: []validator.String{
+					positiveDuration(),
+				},
+			},
+			"kill_signal": schema.StringAttribute{
+				MarkdownDescription: "Signal sent to a timed out or cancelled command and the processes it started. Commands run in their own " +
+					"process group, so shell wrappers do not leave processes behind. One of `SIGTERM`, `SIGINT`, `SIGHUP`, `SIGQUIT` or `SIGKILL`. " +
+					"Defaults to `SIGTERM`. Only the command itself is signalled on Windows.",
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(defaultKillSignal),
+				Validators: []validator.String{
+					stringvalidator.OneOf(commandKillSignalNames...),
+				},
+			},
+			"max_output_bytes": schema.Int64Attribute{
+				MarkdownDescription: "Maximum number of bytes of stdout and stderr, each, to keep in state. Output beyond the limit is discarded " +
+					"and `truncated` is set. Set to `0` to disable the limit. Defaults to 1048576 (1 MiB).",
+				Optional: true,
+				Computed: true,
+				Default:  int64default.StaticInt64(defaultMaxOutputBytes),
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"stdout_log_level": schema.StringAttribute{
+				MarkdownDescription: "Level at which each line of stdout is logged to the Terraform logs as it is written. " +
+					"One of `trace`, `debug`, `info`, `warn`, `error` or `off`. Defaults to `info`.",
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(defaultStdoutLogLevel),
+				Validators: []validator.String{
+					stringvalidator.OneOf(commandLogLevels...),
+				},
+			},
+			"stderr_log_level": schema.StringAttribute{
+				MarkdownDescription: "Level at which each line of stderr is logged to the Terraform logs as it is written. " +
+					"One of `trace`, `debug`, `info`, `warn`, `error` or `off`. Defaults to `warn`.",
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(defaultStderrLogLevel),
+				Validators: []validator.String{
+					stringvalidator.OneOf(commandLogLevels...),
+				},
+			},
+			"output_format": schema.StringAttribute{
+				MarkdownDescription: "Format used to parse stdout into `output_json` (`json`), `output_map` (`dotenv`, KEY=VALUE lines) " +
+					"or `output_lines` (`lines`). One of `raw`, `json`, `dotenv` or `lines`. Defaults to `raw`, which does not parse stdout.",
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(outputFormatRaw),
+				Validators: []validator.String{
+					stringvalidator.OneOf(outputFormats...),
+				},
+			},
 			"stderr": schema.StringAttribute{
 				MarkdownDescription: "Standard error output from the command.",
 				Computed:            true,
@@ -80,17 +282,161 @@ func (r *CommandResource) Schema(ctx context.Context, req resource.SchemaRequest
 				},
 			},
 			"exit_code": schema.Int32Attribute{
-				MarkdownDescription: "Exit code from the command.",
+				MarkdownDescription: "Exit code from the command, or `-1` if it was terminated by a signal.",
 				Computed:            true,
 				PlanModifiers: []planmodifier.Int32{
 					int32planmodifier.UseStateForUnknown(),
 				},
 			},
+			"signaled": schema.BoolAttribute{
+				MarkdownDescription: "Whether the command was terminated by a signal.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"duration": schema.StringAttribute{
+				MarkdownDescription: "Wall-clock time the command took to run.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"truncated": schema.BoolAttribute{
+				MarkdownDescription: "Whether stdout or stderr was truncated to `max_output_bytes`.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"orphans_killed": schema.BoolAttribute{
+				MarkdownDescription: "Whether processes started by a timed out or cancelled command were still running after it exited and had to be killed.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"user_cpu_time": schema.StringAttribute{
+				MarkdownDescription: "User CPU time consumed by the command.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"system_cpu_time": schema.StringAttribute{
+				MarkdownDescription: "System CPU time consumed by the command.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"max_rss_bytes": schema.Int64Attribute{
+				MarkdownDescription: "Maximum resident set size of the command in bytes. Not available on Windows.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"voluntary_context_switches": schema.Int64Attribute{
+				MarkdownDescription: "Number of voluntary context switches of the command, such as when waiting for I/O. Not available on Windows.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"involuntary_context_switches": schema.Int64Attribute{
+				MarkdownDescription: "Number of involuntary context switches of the command, such as when preempted by the scheduler. Not available on Windows.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"output_json": schema.DynamicAttribute{
+				MarkdownDescription: "Stdout decoded as JSON when `output_format` is `json`.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.Dynamic{
+					dynamicplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"output_map": schema.MapAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "KEY=VALUE pairs parsed from stdout when `output_format` is `dotenv`.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"output_lines": schema.ListAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Lines of stdout when `output_format` is `lines`.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.UseStateForUnknown(),
+				},
+			},
 		},
 	}
 }
 
 func (r *CommandResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// The provider has not been configured yet.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*debugProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *debugProviderData, got: %T.", req.ProviderData),
+		)
+		return
+	}
+
+	r.providerData = providerData
+}
+
+func (r *CommandResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if !req.Plan.Raw.IsNull() {
+		resp.Diagnostics.Append(r.providerData.checkMaxDuration(ctx, req.Config, "timeout", "grace_period")...)
+
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// Nothing to do on create or destroy, or when nothing has changed.
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() || req.Plan.Raw.Equal(req.State.Raw) {
+		return
+	}
+
+	var plan, state CommandResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The update command replaces the outputs, so they are only known after apply.
+	if !plan.UpdateCommand.IsNull() {
+		for name, value := range commandResultUnknowns {
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root(name), value)...)
+		}
+	} else if plan.OutputFormat.Equal(state.OutputFormat) {
+		return
+	}
+
+	// The parsed outputs also change with the output format.
+	for name, value := range parsedOutputUnknowns {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root(name), value)...)
+	}
 }
 
 func (r *CommandResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -102,57 +448,300 @@ func (r *CommandResource) Create(ctx context.Context, req resource.CreateRequest
 		return
 	}
 
-	cmd, diags := data.CreateCommand.ToListValue(ctx)
+	var (
+		cmd    []string
+		script string
+		diags  diag.Diagnostics
+	)
+
+	if !data.Script.IsNull() {
+		cmd = []string{data.Interpreter.ValueString()}
+		script = data.Script.ValueString()
+	} else {
+		cmd, diags = commandArgs(ctx, data.CreateCommand)
+		if diags.HasError() {
+			resp.Diagnostics.Append(diags...)
+			return
+		}
+	}
+
+	data.Id = types.StringValue(commandID(cmd, script))
+
+	opts, diags := data.commandOptions(ctx, cmd)
+	if diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+	opts.Script = script
+
+	result, err := runCommand(ctx, opts)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Create Command Failed",
+			"An error occurred while executing the create command: "+err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(result.warnings("create command")...)
+
+	data.setResult(result)
+
+	failed, diags := data.failed(ctx, result)
+	if diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	if failed {
+		resp.Diagnostics.AddError(
+			"Create Command Failed",
+			result.failureDetail("create command"),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(data.parseOutput(ctx)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *CommandResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data CommandResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.ReadCommand.IsNull() {
+		return
+	}
+
+	cmd, diags := commandArgs(ctx, data.ReadCommand)
+	if diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	opts, diags := data.commandOptions(ctx, cmd, data.priorEnv()...)
+	if diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	result, err := runCommand(ctx, opts)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Read Command Failed",
+			"An error occurred while executing the read command: "+err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(result.warnings("read command")...)
+
+	failed, diags := data.failed(ctx, result)
 	if diags.HasError() {
 		resp.Diagnostics.Append(diags...)
 		return
 	}
 
-	if !cmd.IsNull() && !cmd.IsUnknown() {
-		parts := make([]types.String, 0, len(data.CreateCommand.Elements()))
-		diags.Append(data.CreateCommand.ElementsAs(ctx, &parts, false)...)
+	if failed {
+		tflog.Warn(ctx, "Read command failed, removing resource from state", map[string]interface{}{
+			"exit_code": result.ExitCode,
+			"signaled":  result.Signaled,
+		})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	data.setResult(result)
+
+	resp.Diagnostics.Append(data.parseOutput(ctx)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *CommandResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state CommandResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !plan.UpdateCommand.IsNull() {
+		cmd, diags := commandArgs(ctx, plan.UpdateCommand)
+		if diags.HasError() {
+			resp.Diagnostics.Append(diags...)
+			return
+		}
+
+		opts, diags := plan.commandOptions(ctx, cmd, state.priorEnv()...)
 		if diags.HasError() {
 			resp.Diagnostics.Append(diags...)
 			return
 		}
 
-		var cmd []string
-		for _, part := range parts {
-			cmd = append(cmd, part.ValueString())
+		result, err := runCommand(ctx, opts)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Update Command Failed",
+				"An error occurred while executing the update command: "+err.Error(),
+			)
+			return
 		}
 
-		idString := strings.Join(cmd, "\x00")
-		hash := sha256.Sum256([]byte(idString))
-		data.Id = types.StringValue(hex.EncodeToString(hash[:]))
+		resp.Diagnostics.Append(result.warnings("update command")...)
 
-		command := exec.CommandContext(ctx, cmd[0], cmd[1:]...)
+		plan.setResult(result)
 
-		var stderr, stdout strings.Builder
-		command.Stderr = &stderr
-		command.Stdout = &stdout
+		failed, diags := plan.failed(ctx, result)
+		if diags.HasError() {
+			resp.Diagnostics.Append(diags...)
+			return
+		}
 
-		if err := command.Run(); err != nil {
+		if failed {
 			resp.Diagnostics.AddError(
-				"Create Command Failed",
-				"An error occurred while executing the create command: "+err.Error(),
+				"Update Command Failed",
+				result.failureDetail("update command"),
 			)
 			return
 		}
+	} else {
+		plan.keepResult(&state.commandModel)
+	}
 
-		data.Stderr = types.StringValue(stderr.String())
-		data.Stdout = types.StringValue(stdout.String())
-		data.ExitCode = types.Int32Value(int32(command.ProcessState.ExitCode()))
+	resp.Diagnostics.Append(plan.parseOutput(ctx)...)
+
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
 
-func (r *CommandResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+func (r *CommandResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data CommandResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.DestroyCommand.IsNull() {
+		return
+	}
+
+	cmd, diags := commandArgs(ctx, data.DestroyCommand)
+	if diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	opts, diags := data.commandOptions(ctx, cmd, data.priorEnv()...)
+	if diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	result, err := runCommand(ctx, opts)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Destroy Command Failed",
+			"An error occurred while executing the destroy command: "+err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(result.warnings("destroy command")...)
+
+	failed, diags := data.failed(ctx, result)
+	if diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	if failed {
+		resp.Diagnostics.AddError(
+			"Destroy Command Failed",
+			result.failureDetail("destroy command"),
+		)
+		return
+	}
 }
 
-func (r *CommandResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+func (r *CommandResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	var cmd []string
+	if err := json.Unmarshal([]byte(req.ID), &cmd); err != nil || len(cmd) == 0 {
+		resp.Diagnostics.AddError(
+			"Invalid Import ID",
+			`The import ID must be the create command as a non-empty JSON array of strings, e.g. ["echo", "hello"].`,
+		)
+		return
+	}
+
+	createCommand, diags := types.ListValueFrom(ctx, types.StringType, cmd)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The command is not run on import, so only the arguments that are known
+	// and the defaults are set. The outputs are left null.
+	attributes := map[string]attr.Value{
+		"id":                  types.StringValue(commandID(cmd, "")),
+		"create_command":      createCommand,
+		"interpreter":         types.StringValue(defaultInterpreter),
+		"allowed_exit_codes":  types.ListValueMust(types.Int32Type, []attr.Value{types.Int32Value(0)}),
+		"fail_on_nonzero":     types.BoolValue(true),
+		"inherit_environment": types.BoolValue(true),
+		"grace_period":        types.StringValue("5s"),
+		"kill_signal":         types.StringValue(defaultKillSignal),
+		"max_output_bytes":    types.Int64Value(defaultMaxOutputBytes),
+		"stdout_log_level":    types.StringValue(defaultStdoutLogLevel),
+		"stderr_log_level":    types.StringValue(defaultStderrLogLevel),
+		"output_format":       types.StringValue(outputFormatRaw),
+	}
+
+	for name, value := range attributes {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(name), value)...)
+	}
 }
 
-func (r *CommandResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+// priorEnv exposes the outputs of the resource to lifecycle commands as
+// environment variables, so that they can act on the prior state. Outputs
+// that are not set, such as after an import, are exposed as empty strings.
+func (m *CommandResourceModel) priorEnv() []string {
+	exitCode := ""
+	if !m.ExitCode.IsNull() && !m.ExitCode.IsUnknown() {
+		exitCode = strconv.Itoa(int(m.ExitCode.ValueInt32()))
+	}
+
+	return []string{
+		"DEBUG_COMMAND_ID=" + m.Id.ValueString(),
+		"DEBUG_COMMAND_STDOUT=" + m.Stdout.ValueString(),
+		"DEBUG_COMMAND_STDERR=" + m.Stderr.ValueString(),
+		"DEBUG_COMMAND_EXIT_CODE=" + exitCode,
+	}
 }
@@ -0,0 +1,313 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestAccCommandResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCommandResourceConfig,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"debug_command.test",
+						tfjsonpath.New("stdout"),
+						knownvalue.StringExact("hello\n"),
+					),
+					statecheck.ExpectKnownValue(
+						"debug_command.test",
+						tfjsonpath.New("exit_code"),
+						knownvalue.Int32Exact(0),
+					),
+				},
+			},
+			{
+				Config: testAccCommandResourceUpdateConfig,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"debug_command.test",
+						tfjsonpath.New("stdout"),
+						knownvalue.StringExact("hello\nupdated\n"),
+					),
+				},
+			},
+		},
+	})
+}
+
+const testAccCommandResourceConfig = `
+resource "debug_command" "test" {
+  create_command = ["echo", "hello"]
+  read_command   = ["sh", "-c", "printf '%s' \"$DEBUG_COMMAND_STDOUT\""]
+}
+`
+
+const testAccCommandResourceUpdateConfig = `
+resource "debug_command" "test" {
+  create_command = ["echo", "hello"]
+  read_command   = ["sh", "-c", "printf '%s' \"$DEBUG_COMMAND_STDOUT\""]
+  update_command = ["sh", "-c", "printf '%s' \"$DEBUG_COMMAND_STDOUT\"; echo updated"]
+}
+`
+
+func TestAccCommandResource_nonzeroExit(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCommandResourceNonzeroExitConfig,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"debug_command.test",
+						tfjsonpath.New("exit_code"),
+						knownvalue.Int32Exact(3),
+					),
+					statecheck.ExpectKnownValue(
+						"debug_command.test",
+						tfjsonpath.New("stderr"),
+						knownvalue.StringExact("oops\n"),
+					),
+					statecheck.ExpectKnownValue(
+						"debug_command.test",
+						tfjsonpath.New("signaled"),
+						knownvalue.Bool(false),
+					),
+				},
+			},
+		},
+	})
+}
+
+const testAccCommandResourceNonzeroExitConfig = `
+resource "debug_command" "test" {
+  create_command  = ["sh", "-c", "echo oops >&2; exit 3"]
+  fail_on_nonzero = false
+}
+`
+
+func TestAccCommandResource_environment(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCommandResourceEnvironmentConfig,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"debug_command.test",
+						tfjsonpath.New("stdout"),
+						knownvalue.StringExact("/ bar input TF_ACC="),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestAccCommandResource_timeout(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCommandResourceTimeoutConfig,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"debug_command.test",
+						tfjsonpath.New("signaled"),
+						knownvalue.Bool(true),
+					),
+				},
+			},
+		},
+	})
+}
+
+const testAccCommandResourceEnvironmentConfig = `
+resource "debug_command" "test" {
+  create_command      = ["/bin/sh", "-c", "printf '%s %s %s TF_ACC=%s' \"$(pwd)\" \"$FOO\" \"$(cat)\" \"$TF_ACC\""]
+  inherit_environment = false
+  working_dir         = "/"
+  stdin_base64        = base64encode("input")
+
+  environment = {
+    FOO = "bar"
+  }
+}
+`
+
+const testAccCommandResourceTimeoutConfig = `
+resource "debug_command" "test" {
+  create_command  = ["sleep", "60"]
+  timeout         = "1s"
+  grace_period    = "1s"
+  fail_on_nonzero = false
+}
+`
+
+func TestAccCommandResource_outputFormat(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCommandResourceOutputFormatConfig("json"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"debug_command.test",
+						tfjsonpath.New("output_json").AtSliceIndex(0),
+						knownvalue.StringExact("a"),
+					),
+				},
+			},
+			{
+				Config: testAccCommandResourceOutputFormatConfig("lines"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"debug_command.test",
+						tfjsonpath.New("output_lines"),
+						knownvalue.ListExact([]knownvalue.Check{
+							knownvalue.StringExact(`["a",`),
+							knownvalue.StringExact(`"b"]`),
+						}),
+					),
+					statecheck.ExpectKnownValue(
+						"debug_command.test",
+						tfjsonpath.New("output_json"),
+						knownvalue.Null(),
+					),
+				},
+			},
+		},
+	})
+}
+
+func testAccCommandResourceOutputFormatConfig(format string) string {
+	return fmt.Sprintf(`
+resource "debug_command" "test" {
+  create_command = ["printf", "[\"a\",\n\"b\"]\n"]
+  output_format  = %q
+}
+`, format)
+}
+
+func TestAccCommandResource_import(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "debug_command" "test" {
+  create_command = ["echo", "hello"]
+}
+`,
+			},
+			{
+				ResourceName:      "debug_command.test",
+				ImportState:       true,
+				ImportStateId:     `["echo", "hello"]`,
+				ImportStateVerify: true,
+				// The command is not run on import.
+				ImportStateVerifyIgnore: []string{
+					"stdout", "stderr", "exit_code", "signaled", "duration", "truncated", "orphans_killed",
+					"user_cpu_time", "system_cpu_time", "max_rss_bytes", "voluntary_context_switches", "involuntary_context_switches",
+				},
+			},
+		},
+	})
+}
+
+func TestAccCommandResource_importPriorEnv(t *testing.T) {
+	config := `
+resource "debug_command" "test" {
+  create_command = ["echo", "hello"]
+  read_command   = ["sh", "-c", "printf 'exit_code=%s' \"$DEBUG_COMMAND_EXIT_CODE\""]
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:             config,
+				ResourceName:       "debug_command.test",
+				ImportState:        true,
+				ImportStateId:      `["echo", "hello"]`,
+				ImportStatePersist: true,
+			},
+			{
+				// The update adds the read command without running any.
+				Config: config,
+			},
+			{
+				// The imported command never ran, so the read command is not
+				// told that it succeeded.
+				Config: config,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"debug_command.test",
+						tfjsonpath.New("stdout"),
+						knownvalue.StringExact("exit_code="),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestAccCommandResource_importThenUpdate(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "debug_command" "test" {
+  create_command = ["echo", "hello"]
+}
+`,
+				ResourceName:       "debug_command.test",
+				ImportState:        true,
+				ImportStateId:      `["echo", "hello"]`,
+				ImportStatePersist: true,
+			},
+			{
+				// No command runs on update, so the outputs left empty by the
+				// import must stay known.
+				Config: `
+resource "debug_command" "test" {
+  create_command = ["echo", "hello"]
+  output_format  = "lines"
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"debug_command.test",
+						tfjsonpath.New("stdout"),
+						knownvalue.Null(),
+					),
+					statecheck.ExpectKnownValue(
+						"debug_command.test",
+						tfjsonpath.New("output_lines"),
+						knownvalue.Null(),
+					),
+				},
+			},
+		},
+	})
+}
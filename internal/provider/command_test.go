@@ -0,0 +1,167 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+)
+
+func TestLineLogger(t *testing.T) {
+	longLine := strings.Repeat("x", maxLogLineBytes)
+
+	tests := []struct {
+		name   string
+		level  string
+		writes []string
+		want   []string
+	}{
+		{
+			name:   "lines",
+			level:  "info",
+			writes: []string{"one\ntwo\n"},
+			want:   []string{"one", "two"},
+		},
+		{
+			name:   "line split across writes",
+			level:  "info",
+			writes: []string{"on", "e\ntw", "o\n"},
+			want:   []string{"one", "two"},
+		},
+		{
+			name:   "crlf",
+			level:  "info",
+			writes: []string{"one\r\ntwo\r\n"},
+			want:   []string{"one", "two"},
+		},
+		{
+			name:   "final line without newline",
+			level:  "info",
+			writes: []string{"one\ntwo"},
+			want:   []string{"one", "two"},
+		},
+		{
+			// A line reaching the cap is logged without waiting for its
+			// newline, and the rest of it is logged as a line of its own.
+			name:   "line cap",
+			level:  "info",
+			writes: []string{longLine, "rest\n"},
+			want:   []string{longLine, "rest"},
+		},
+		{
+			name:   "off",
+			level:  "off",
+			writes: []string{"one\ntwo"},
+			want:   nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output bytes.Buffer
+			ctx := tflogtest.RootLogger(context.Background(), &output)
+
+			logger := &lineLogger{ctx: ctx, stream: "stdout", level: tt.level}
+			for _, write := range tt.writes {
+				n, err := logger.Write([]byte(write))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if n != len(write) {
+					t.Fatalf("expected %d bytes to be written, got %d", len(write), n)
+				}
+			}
+			logger.Flush()
+
+			entries, err := tflogtest.MultilineJSONDecode(&output)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			for _, entry := range entries {
+				if entry["@level"] != tt.level {
+					t.Errorf("expected level %s, got %v", tt.level, entry["@level"])
+				}
+				if entry["stream"] != "stdout" {
+					t.Errorf("expected stream stdout, got %v", entry["stream"])
+				}
+				got = append(got, entry["@message"].(string))
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("expected %d lines to be logged, got %d: %q", len(tt.want), len(got), got)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("expected line %d to be %q, got %q", i, tt.want[i], got[i])
+				}
+			}
+		})
+	}
+}
+
+func TestLimitedBuffer(t *testing.T) {
+	tests := []struct {
+		name          string
+		limit         int64
+		writes        []string
+		want          string
+		wantTruncated bool
+	}{
+		{
+			name:   "below limit",
+			limit:  10,
+			writes: []string{"hello"},
+			want:   "hello",
+		},
+		{
+			name:   "at limit",
+			limit:  5,
+			writes: []string{"hel", "lo"},
+			want:   "hello",
+		},
+		{
+			name:          "over limit",
+			limit:         4,
+			writes:        []string{"hel", "lo", " world"},
+			want:          "hell",
+			wantTruncated: true,
+		},
+		{
+			name:   "unlimited",
+			limit:  0,
+			writes: []string{strings.Repeat("x", 1<<20)},
+			want:   strings.Repeat("x", 1<<20),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &limitedBuffer{limit: tt.limit}
+			for _, write := range tt.writes {
+				// Discarded bytes are still reported as written, so that the
+				// command does not fail writing its output.
+				n, err := b.Write([]byte(write))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if n != len(write) {
+					t.Fatalf("expected %d bytes to be written, got %d", len(write), n)
+				}
+			}
+
+			if got := b.String(); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+			if b.truncated != tt.wantTruncated {
+				t.Errorf("expected truncated to be %t, got %t", tt.wantTruncated, b.truncated)
+			}
+		})
+	}
+}
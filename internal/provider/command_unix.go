@@ -0,0 +1,56 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build unix
+
+package provider
+
+import (
+	"os"
+	"os/exec"
+	"runtime"
+	"syscall"
+)
+
+// setProcessGroup starts the command in a new process group, so that it can
+// be signalled along with the processes it spawns.
+func setProcessGroup(command *exec.Cmd) {
+	command.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// signalProcessGroup sends sig to the process group led by process.
+func signalProcessGroup(process *os.Process, sig syscall.Signal) error {
+	return syscall.Kill(-process.Pid, sig)
+}
+
+// killProcessGroup kills the processes left in the process group led by the
+// exited process, and reports whether there were any.
+func killProcessGroup(process *os.Process) bool {
+	// Signal 0 only checks whether any process is left in the group.
+	if err := syscall.Kill(-process.Pid, 0); err != nil {
+		return false
+	}
+
+	return syscall.Kill(-process.Pid, syscall.SIGKILL) == nil
+}
+
+// resourceUsage returns the resource usage of an exited process from its
+// rusage.
+func resourceUsage(state *os.ProcessState) *processUsage {
+	rusage, ok := state.SysUsage().(*syscall.Rusage)
+	if !ok {
+		return nil
+	}
+
+	// ru_maxrss is reported in bytes on Darwin and in kilobytes elsewhere.
+	maxRSS := int64(rusage.Maxrss)
+	if runtime.GOOS != "darwin" && runtime.GOOS != "ios" {
+		maxRSS *= 1024
+	}
+
+	return &processUsage{
+		MaxRSS:                     maxRSS,
+		VoluntaryContextSwitches:   int64(rusage.Nvcsw),
+		InvoluntaryContextSwitches: int64(rusage.Nivcsw),
+	}
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
@@ -23,6 +24,7 @@ func NewCPUHogDataSource() datasource.DataSource {
 }
 
 type CPUHogDataSource struct {
+	providerData *debugProviderData
 }
 
 type CPUHogDataSourceModel struct {
@@ -46,18 +48,36 @@ func (d *CPUHogDataSource) Schema(ctx context.Context, req datasource.SchemaRequ
 			"duration": schema.StringAttribute{
 				MarkdownDescription: "The duration for which to hog the CPU. Defaults to 30 seconds.",
 				Optional:            true,
+				Validators: []validator.String{
+					positiveDuration(),
+				},
 			},
 		},
 	}
 }
 
 func (d *CPUHogDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// The provider has not been configured yet.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*debugProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *debugProviderData, got: %T.", req.ProviderData),
+		)
+		return
+	}
 
+	d.providerData = providerData
 }
 
 func (d *CPUHogDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
 	var data CPUHogDataSourceModel
 	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	resp.Diagnostics.Append(d.providerData.checkMaxDuration(ctx, req.Config, "duration")...)
 
 	if resp.Diagnostics.HasError() {
 		return
@@ -88,14 +108,18 @@ func (d *CPUHogDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 		return
 	}
 
-	durationStr := data.Duration.ValueString()
-	duration, err := time.ParseDuration(durationStr)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Invalid Duration",
-			fmt.Sprintf("Failed to parse duration '%s': %v", durationStr, err),
-		)
-		return
+	duration := 30 * time.Second
+	if !data.Duration.IsNull() {
+		durationStr := data.Duration.ValueString()
+		var err error
+		duration, err = time.ParseDuration(durationStr)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Invalid Duration",
+				fmt.Sprintf("Failed to parse duration '%s': %v", durationStr, err),
+			)
+			return
+		}
 	}
 
 	// Set the maximum number of CPUs to use
@@ -0,0 +1,74 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Modes accepted by the crash resource and data source.
+const (
+	crashModePanic       = "panic"
+	crashModeExit        = "exit"
+	crashModeSigkillSelf = "sigkill_self"
+	crashModeCloseStdout = "close_stdout"
+	crashModeHangForever = "hang_forever"
+)
+
+var crashModes = []string{crashModePanic, crashModeExit, crashModeSigkillSelf, crashModeCloseStdout, crashModeHangForever}
+
+// defaultCrashExitCode is the exit code used by the exit mode when none is
+// configured.
+const defaultCrashExitCode = 1
+
+// crash makes the provider process misbehave according to mode. It only
+// returns on error.
+func crash(ctx context.Context, mode string, exitCode int, phase string) error {
+	tflog.Warn(ctx, "Crashing provider", map[string]interface{}{
+		"mode":  mode,
+		"phase": phase,
+	})
+
+	switch mode {
+	case crashModePanic:
+		panic(fmt.Sprintf("debug_crash: panic during %s", phase))
+	case crashModeExit:
+		os.Exit(exitCode)
+	case crashModeSigkillSelf:
+		self, err := os.FindProcess(os.Getpid())
+		if err != nil {
+			return err
+		}
+		if err := self.Kill(); err != nil {
+			return err
+		}
+		// Wait for the signal to be delivered.
+		select {}
+	case crashModeCloseStdout:
+		// The plugin server forwards the output of the provider to Terraform
+		// through these, so closing them silences the provider, and the
+		// gRPC connection is dropped so that Terraform sees the plugin go
+		// away while the process lingers.
+		if err := os.Stdout.Close(); err != nil {
+			return err
+		}
+		if err := os.Stderr.Close(); err != nil {
+			return err
+		}
+		if err := disconnect(); err != nil {
+			return err
+		}
+		// Wait for Terraform to kill the process.
+		select {}
+	case crashModeHangForever:
+		// Ignore cancellation, so that only killing the process stops it.
+		select {}
+	}
+
+	return fmt.Errorf("unknown crash mode %q", mode)
+}
@@ -0,0 +1,81 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ datasource.DataSource = &CrashDataSource{}
+
+func NewCrashDataSource() datasource.DataSource {
+	return &CrashDataSource{}
+}
+
+type CrashDataSource struct {
+}
+
+type CrashDataSourceModel struct {
+	Mode     types.String `tfsdk:"mode"`
+	ExitCode types.Int64  `tfsdk:"exit_code"`
+}
+
+func (d *CrashDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_crash"
+}
+
+func (d *CrashDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Crashes the provider process when the data source is read. " +
+			"Useful for reproducing how Terraform handles a provider that panics, exits unexpectedly or stops responding.",
+
+		Attributes: map[string]schema.Attribute{
+			"mode": schema.StringAttribute{
+				MarkdownDescription: "How the provider crashes. One of `panic`, `exit` (exits with `exit_code`), `sigkill_self` (kills its own process), " +
+					"`close_stdout` (closes its standard output and error and drops its gRPC connection, so that Terraform reports that the plugin exited unexpectedly while the process lingers; on Windows it exits instead) or `hang_forever` (never returns, even when cancelled).",
+				Required: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(crashModes...),
+				},
+			},
+			"exit_code": schema.Int64Attribute{
+				MarkdownDescription: "Exit code of the provider process in the `exit` mode. Defaults to `1`.",
+				Optional:            true,
+			},
+		},
+	}
+}
+
+func (d *CrashDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+}
+
+func (d *CrashDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data CrashDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	exitCode := int64(defaultCrashExitCode)
+	if !data.ExitCode.IsNull() {
+		exitCode = data.ExitCode.ValueInt64()
+	}
+
+	if err := crash(ctx, data.Mode.ValueString(), int(exitCode), "read"); err != nil {
+		resp.Diagnostics.AddError(
+			"Crash Failed",
+			"An error occurred while crashing the provider: "+err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
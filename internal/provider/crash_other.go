@@ -0,0 +1,16 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build !unix
+
+package provider
+
+import "os"
+
+// disconnect cannot shut down the sockets of the process on this platform,
+// so the process exits instead, which also drops the gRPC connection to
+// Terraform.
+func disconnect() error {
+	os.Exit(1)
+	return nil
+}
@@ -0,0 +1,181 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ resource.Resource = &CrashResource{}
+var _ resource.ResourceWithModifyPlan = &CrashResource{}
+
+// Lifecycle phases at which the crash resource can crash.
+const (
+	crashPhasePlan   = "plan"
+	crashPhaseCreate = "create"
+	crashPhaseRead   = "read"
+	crashPhaseUpdate = "update"
+	crashPhaseDelete = "delete"
+)
+
+var crashPhases = []string{crashPhasePlan, crashPhaseCreate, crashPhaseRead, crashPhaseUpdate, crashPhaseDelete}
+
+func NewCrashResource() resource.Resource {
+	return &CrashResource{}
+}
+
+type CrashResource struct {
+}
+
+type CrashResourceModel struct {
+	Mode     types.String `tfsdk:"mode"`
+	Phase    types.String `tfsdk:"phase"`
+	ExitCode types.Int64  `tfsdk:"exit_code"`
+}
+
+func (r *CrashResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_crash"
+}
+
+func (r *CrashResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Crashes the provider process at a chosen lifecycle phase. " +
+			"Useful for reproducing how Terraform handles a provider that panics, exits unexpectedly or stops responding.",
+
+		Attributes: map[string]schema.Attribute{
+			"mode": schema.StringAttribute{
+				MarkdownDescription: "How the provider crashes. One of `panic`, `exit` (exits with `exit_code`), `sigkill_self` (kills its own process), " +
+					"`close_stdout` (closes its standard output and error and drops its gRPC connection, so that Terraform reports that the plugin exited unexpectedly while the process lingers; on Windows it exits instead) or `hang_forever` (never returns, even when cancelled).",
+				Required: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(crashModes...),
+				},
+			},
+			"phase": schema.StringAttribute{
+				MarkdownDescription: "Lifecycle phase at which the provider crashes. One of `plan`, `create`, `read`, `update` or `delete`. " +
+					"The `read` and `delete` phases use the value in state, so they take effect once the resource has been applied. Defaults to `create`.",
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(crashPhaseCreate),
+				Validators: []validator.String{
+					stringvalidator.OneOf(crashPhases...),
+				},
+			},
+			"exit_code": schema.Int64Attribute{
+				MarkdownDescription: "Exit code of the provider process in the `exit` mode. Defaults to `1`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(defaultCrashExitCode),
+			},
+		},
+	}
+}
+
+func (r *CrashResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+}
+
+func (r *CrashResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// The resource is being destroyed.
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var data CrashResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(data.crashAt(ctx, crashPhasePlan)...)
+}
+
+func (r *CrashResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data CrashResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(data.crashAt(ctx, crashPhaseCreate)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *CrashResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data CrashResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(data.crashAt(ctx, crashPhaseRead)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *CrashResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data CrashResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(data.crashAt(ctx, crashPhaseUpdate)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *CrashResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data CrashResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(data.crashAt(ctx, crashPhaseDelete)...)
+}
+
+// crashAt crashes the provider if phase is the configured phase.
+func (m *CrashResourceModel) crashAt(ctx context.Context, phase string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if m.Phase.ValueString() != phase {
+		return diags
+	}
+
+	if err := crash(ctx, m.Mode.ValueString(), int(m.ExitCode.ValueInt64()), phase); err != nil {
+		diags.AddError(
+			"Crash Failed",
+			"An error occurred while crashing the provider: "+err.Error(),
+		)
+	}
+
+	return diags
+}
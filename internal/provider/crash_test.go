@@ -0,0 +1,192 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// crashTestModeEnv makes TestCrash crash the test binary in the given mode
+// instead of running the tests, so that the crash can be observed from the
+// parent test process.
+const crashTestModeEnv = "DEBUG_CRASH_TEST_MODE"
+
+// crashTestAddrEnv is the address the process crashed by TestCrash connects
+// to before crashing.
+const crashTestAddrEnv = "DEBUG_CRASH_TEST_ADDR"
+
+// crashTestExitCode is the exit code used by the exit mode in TestCrash.
+const crashTestExitCode = 3
+
+func TestCrash(t *testing.T) {
+	if mode := os.Getenv(crashTestModeEnv); mode != "" {
+		crashTestProcess(mode)
+		return
+	}
+
+	tests := []struct {
+		mode  string
+		check func(t *testing.T, err error, stdout, stderr string)
+	}{
+		{
+			mode: crashModePanic,
+			check: func(t *testing.T, err error, stdout, stderr string) {
+				if code := exitCode(t, err); code != 2 {
+					t.Errorf("expected exit code 2, got %d", code)
+				}
+				if !strings.Contains(stderr, "panic: debug_crash: panic during create") {
+					t.Errorf("expected a panic on stderr, got: %s", stderr)
+				}
+			},
+		},
+		{
+			mode: crashModeExit,
+			check: func(t *testing.T, err error, stdout, stderr string) {
+				if code := exitCode(t, err); code != crashTestExitCode {
+					t.Errorf("expected exit code %d, got %d", crashTestExitCode, code)
+				}
+			},
+		},
+		{
+			mode: crashModeSigkillSelf,
+			check: func(t *testing.T, err error, stdout, stderr string) {
+				if runtime.GOOS == "windows" {
+					if exitCode(t, err) == 0 {
+						t.Error("expected the process to be killed")
+					}
+					return
+				}
+				// The exit code is -1 when the process was killed by a signal.
+				if code := exitCode(t, err); code != -1 {
+					t.Errorf("expected the process to be killed by a signal, got exit code %d", code)
+				}
+			},
+		},
+		{
+			mode: crashModeHangForever,
+			check: func(t *testing.T, err error, stdout, stderr string) {
+				if !errors.Is(err, context.DeadlineExceeded) {
+					t.Errorf("expected the process to hang until killed, got: %v", err)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			var stdout, stderr bytes.Buffer
+			cmd := exec.CommandContext(ctx, os.Args[0], "-test.run=^TestCrash$")
+			cmd.Env = append(os.Environ(), crashTestModeEnv+"="+tt.mode)
+			cmd.Stdout = &stdout
+			cmd.Stderr = &stderr
+
+			err := cmd.Run()
+			if ctx.Err() != nil {
+				err = ctx.Err()
+			}
+
+			tt.check(t, err, stdout.String(), stderr.String())
+		})
+	}
+}
+
+// crashTestProcess crashes the current process in mode. If
+// crashTestAddrEnv is set, it first connects to that address, standing in for
+// the gRPC connection to Terraform.
+func crashTestProcess(mode string) {
+	if addr := os.Getenv(crashTestAddrEnv); addr != "" {
+		conn, err := net.Dial("tcp", addr)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(100)
+		}
+		defer conn.Close()
+	}
+
+	if err := crash(context.Background(), mode, crashTestExitCode, "create"); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(100)
+	}
+
+	// crash does not return without an error.
+	os.Exit(101)
+}
+
+func exitCode(t *testing.T, err error) int {
+	t.Helper()
+
+	if err == nil {
+		return 0
+	}
+
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		t.Fatalf("expected the process to exit, got: %s", err)
+	}
+	return exitErr.ExitCode()
+}
+
+func TestCrash_closeStdout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the process exits instead of disconnecting on Windows")
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestCrash$")
+	cmd.Env = append(os.Environ(),
+		crashTestModeEnv+"="+crashModeCloseStdout,
+		crashTestAddrEnv+"="+listener.Addr().String(),
+	)
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+	defer func() {
+		_ = cmd.Process.Kill()
+		<-exited
+	}()
+
+	conn, err := listener.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// The connection is dropped, rather than the read timing out.
+	if err := conn.SetReadDeadline(time.Now().Add(5 * time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conn.Read(make([]byte, 1)); !errors.Is(err, io.EOF) && !errors.Is(err, syscall.ECONNRESET) {
+		t.Fatalf("expected the connection to be dropped, got: %v", err)
+	}
+
+	// The process lingers after the disconnect.
+	select {
+	case err := <-exited:
+		t.Fatalf("expected the process to keep running, exited with: %v", err)
+	case <-time.After(500 * time.Millisecond):
+	}
+}
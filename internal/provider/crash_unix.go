@@ -0,0 +1,41 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build unix
+
+package provider
+
+import (
+	"os"
+	"strconv"
+	"syscall"
+)
+
+// disconnect shuts down every socket of the process, which drops the gRPC
+// connection to Terraform while the process keeps running. The sockets are
+// shut down rather than closed, so that the runtime still owns their file
+// descriptors.
+func disconnect() error {
+	entries, err := os.ReadDir("/dev/fd")
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		fd, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+
+		var stat syscall.Stat_t
+		if err := syscall.Fstat(fd, &stat); err != nil || uint32(stat.Mode)&syscall.S_IFMT != syscall.S_IFSOCK {
+			continue
+		}
+
+		// Listening sockets cannot be shut down on every platform, which
+		// does not matter once the connections are gone.
+		_ = syscall.Shutdown(fd, syscall.SHUT_RDWR)
+	}
+
+	return nil
+}
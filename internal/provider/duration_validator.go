@@ -0,0 +1,102 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ validator.String = durationValidator{}
+
+// durationValidator validates that a string is a positive duration, such as
+// '30s' or '1m30s', so that typos are reported when the configuration is
+// validated rather than when the duration is used.
+type durationValidator struct{}
+
+func (v durationValidator) Description(ctx context.Context) string {
+	return "value must be a positive duration, such as '30s' or '1m30s'"
+}
+
+func (v durationValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v durationValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	duration, err := time.ParseDuration(req.ConfigValue.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Duration",
+			"Could not parse duration: "+err.Error(),
+		)
+		return
+	}
+
+	if duration <= 0 {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Duration",
+			"The duration must be greater than zero, got "+req.ConfigValue.ValueString()+".",
+		)
+	}
+}
+
+// positiveDuration returns a validator that checks that a string is a
+// positive duration.
+func positiveDuration() validator.String {
+	return durationValidator{}
+}
+
+// checkMaxDuration checks that none of the named duration attributes of config
+// exceeds the provider's max_duration. The provider configuration is not
+// available when the configuration is validated, so this is called when
+// planning resources and reading data sources instead. Values that cannot be
+// parsed are left to positiveDuration.
+func (d *debugProviderData) checkMaxDuration(ctx context.Context, config tfsdk.Config, attributes ...string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if d == nil || d.maxDuration == 0 {
+		return diags
+	}
+
+	for _, attribute := range attributes {
+		var value types.String
+		diags.Append(config.GetAttribute(ctx, path.Root(attribute), &value)...)
+
+		if diags.HasError() {
+			return diags
+		}
+
+		if value.IsNull() || value.IsUnknown() {
+			continue
+		}
+
+		duration, err := time.ParseDuration(value.ValueString())
+		if err != nil {
+			continue
+		}
+
+		if duration > d.maxDuration {
+			diags.AddAttributeError(
+				path.Root(attribute),
+				"Invalid Duration",
+				fmt.Sprintf("The duration must not exceed the provider's max_duration of %s, got %s.", d.maxDuration, value.ValueString()),
+			)
+		}
+	}
+
+	return diags
+}
@@ -0,0 +1,159 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Severities accepted by the diagnostics of the failure resource and data
+// source.
+const (
+	failureSeverityError   = "error"
+	failureSeverityWarning = "warning"
+)
+
+var failureSeverities = []string{failureSeverityError, failureSeverityWarning}
+
+// failureDiagnosticModel is an entry of the diagnostics attribute of the
+// failure resource and data source.
+type failureDiagnosticModel struct {
+	Severity  types.String `tfsdk:"severity"`
+	Summary   types.String `tfsdk:"summary"`
+	Detail    types.String `tfsdk:"detail"`
+	Attribute types.String `tfsdk:"attribute"`
+}
+
+// failureDiagnosticType is the type of an entry of the diagnostics attribute.
+var failureDiagnosticType = types.ObjectType{
+	AttrTypes: map[string]attr.Type{
+		"severity":  types.StringType,
+		"summary":   types.StringType,
+		"detail":    types.StringType,
+		"attribute": types.StringType,
+	},
+}
+
+// failureDiagnostics converts the configured diagnostics into diagnostics to
+// emit, in order.
+func failureDiagnostics(ctx context.Context, list types.List) diag.Diagnostics {
+	var entries []failureDiagnosticModel
+
+	diags := list.ElementsAs(ctx, &entries, false)
+	if diags.HasError() {
+		return diags
+	}
+
+	for i, entry := range entries {
+		summary := entry.Summary.ValueString()
+		detail := entry.Detail.ValueString()
+		warning := entry.Severity.ValueString() == failureSeverityWarning
+
+		if entry.Attribute.IsNull() {
+			if warning {
+				diags.AddWarning(summary, detail)
+			} else {
+				diags.AddError(summary, detail)
+			}
+			continue
+		}
+
+		attributePath, err := parseAttributePath(entry.Attribute.ValueString())
+		if err != nil {
+			diags.AddAttributeError(
+				path.Root("diagnostics").AtListIndex(i).AtName("attribute"),
+				"Invalid Attribute Path",
+				"Could not parse attribute: "+err.Error(),
+			)
+			continue
+		}
+
+		if warning {
+			diags.AddAttributeWarning(attributePath, summary, detail)
+		} else {
+			diags.AddAttributeError(attributePath, summary, detail)
+		}
+	}
+
+	return diags
+}
+
+// failureMessage returns the configured message, or def if it is not set.
+func failureMessage(message types.String, def string) string {
+	if message.IsNull() || message.IsUnknown() {
+		return def
+	}
+	return message.ValueString()
+}
+
+// parseAttributePath parses an attribute path made of attribute names
+// separated by dots, list indexes such as [0] and map keys such as ["key"].
+func parseAttributePath(s string) (path.Path, error) {
+	var p path.Path
+
+	rest := s
+	for first := true; first || rest != ""; first = false {
+		if !first && strings.HasPrefix(rest, "[") {
+			rest = rest[1:]
+
+			if quoted, err := strconv.QuotedPrefix(rest); err == nil {
+				key, _ := strconv.Unquote(quoted)
+				p = p.AtMapKey(key)
+				rest = rest[len(quoted):]
+			} else {
+				end := strings.IndexByte(rest, ']')
+				if end < 0 {
+					return path.Empty(), fmt.Errorf("%q has an unterminated [", s)
+				}
+
+				index, err := strconv.Atoi(rest[:end])
+				if err != nil || index < 0 {
+					return path.Empty(), fmt.Errorf("%q has an invalid index [%s]", s, rest[:end])
+				}
+				p = p.AtListIndex(index)
+				rest = rest[end:]
+			}
+
+			if !strings.HasPrefix(rest, "]") {
+				return path.Empty(), fmt.Errorf("%q has an unterminated [", s)
+			}
+			rest = rest[1:]
+			continue
+		}
+
+		if !first {
+			if !strings.HasPrefix(rest, ".") {
+				return path.Empty(), fmt.Errorf("%q has unexpected characters after ]", s)
+			}
+			rest = rest[1:]
+		}
+
+		end := strings.IndexAny(rest, ".[")
+		if end < 0 {
+			end = len(rest)
+		}
+
+		name := rest[:end]
+		if name == "" {
+			return path.Empty(), fmt.Errorf("%q has an empty attribute name", s)
+		}
+
+		if first {
+			p = path.Root(name)
+		} else {
+			p = p.AtName(name)
+		}
+		rest = rest[end:]
+	}
+
+	return p, nil
+}
@@ -6,8 +6,12 @@ package provider
 import (
 	"context"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 var _ datasource.DataSource = &FailureDataSource{}
@@ -20,6 +24,11 @@ type FailureDataSource struct {
 }
 
 type FailureDataSourceModel struct {
+	Enabled     types.Bool   `tfsdk:"enabled"`
+	Condition   types.Bool   `tfsdk:"condition"`
+	Message     types.String `tfsdk:"message"`
+	Severity    types.String `tfsdk:"severity"`
+	Diagnostics types.List   `tfsdk:"diagnostics"`
 }
 
 func (d *FailureDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
@@ -28,8 +37,59 @@ func (d *FailureDataSource) Metadata(ctx context.Context, req datasource.Metadat
 
 func (d *FailureDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		MarkdownDescription: "Failure data source",
-		Attributes:          map[string]schema.Attribute{},
+		MarkdownDescription: "Failure data source that fails, or warns, when it is read. " +
+			"With `condition`, it can guard a configuration, such as failing the plan when the agent has too little memory.",
+		Attributes: map[string]schema.Attribute{
+			"enabled": schema.BoolAttribute{
+				MarkdownDescription: "Whether the failure is triggered. Defaults to `true`.",
+				Optional:            true,
+			},
+			"condition": schema.BoolAttribute{
+				MarkdownDescription: "Condition under which the failure is triggered, such as " +
+					"`data.debug_system_info.this.memory_total < 4 * 1024 * 1024 * 1024`. Defaults to `true`.",
+				Optional: true,
+			},
+			"message": schema.StringAttribute{
+				MarkdownDescription: "Detail of the diagnostic emitted when the failure is triggered. Defaults to a generic message.",
+				Optional:            true,
+			},
+			"severity": schema.StringAttribute{
+				MarkdownDescription: "Severity of the diagnostic emitted when the failure is triggered. One of `error` or `warning`. " +
+					"With `warning`, the read succeeds. Defaults to `error`.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(failureSeverities...),
+				},
+			},
+			"diagnostics": schema.ListNestedAttribute{
+				MarkdownDescription: "Diagnostics emitted, in order, instead of `message` when the failure is triggered. " +
+					"If none of them is an error, the read succeeds with warnings.",
+				Optional: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"severity": schema.StringAttribute{
+							MarkdownDescription: "Severity of the diagnostic. One of `error` or `warning`. Defaults to `error`.",
+							Optional:            true,
+							Validators: []validator.String{
+								stringvalidator.OneOf(failureSeverities...),
+							},
+						},
+						"summary": schema.StringAttribute{
+							MarkdownDescription: "Summary of the diagnostic.",
+							Required:            true,
+						},
+						"detail": schema.StringAttribute{
+							MarkdownDescription: "Detail of the diagnostic.",
+							Optional:            true,
+						},
+						"attribute": schema.StringAttribute{
+							MarkdownDescription: "Path of the attribute the diagnostic applies to, such as `diagnostics[0].summary`.",
+							Optional:            true,
+						},
+					},
+				},
+			},
+		},
 	}
 }
 
@@ -45,8 +105,32 @@ func (d *FailureDataSource) Read(ctx context.Context, req datasource.ReadRequest
 		return
 	}
 
-	resp.Diagnostics.AddError(
-		"Failure Data Source Error",
-		"An error occurred while reading the failure data source.",
-	)
+	enabled := data.Enabled.IsNull() || data.Enabled.ValueBool()
+	triggered := data.Condition.IsNull() || data.Condition.ValueBool()
+
+	switch {
+	case !enabled || !triggered:
+		tflog.Info(ctx, "Failure not triggered", map[string]interface{}{
+			"enabled":   enabled,
+			"condition": triggered,
+		})
+	case !data.Diagnostics.IsNull():
+		resp.Diagnostics.Append(failureDiagnostics(ctx, data.Diagnostics)...)
+	case data.Severity.ValueString() == failureSeverityWarning:
+		resp.Diagnostics.AddWarning(
+			"Failure Data Source Warning",
+			failureMessage(data.Message, "A warning occurred while reading the failure data source."),
+		)
+	default:
+		resp.Diagnostics.AddError(
+			"Failure Data Source Error",
+			failureMessage(data.Message, "An error occurred while reading the failure data source."),
+		)
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
@@ -0,0 +1,85 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccFailureDataSource_diagnostics(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// Warnings alone do not fail the read.
+				Config: `
+data "debug_failure" "test" {
+  diagnostics = [
+    {
+      severity = "warning"
+      summary  = "First Warning"
+    },
+  ]
+}
+`,
+			},
+			{
+				Config: `
+data "debug_failure" "test" {
+  diagnostics = [
+    {
+      summary = "First Error"
+      detail  = "The first error."
+    },
+    {
+      summary   = "Second Error"
+      attribute = "diagnostics[1].summary"
+    },
+  ]
+}
+`,
+				ExpectError: regexp.MustCompile(`(?s)First Error.*The first error.*Second Error`),
+			},
+		},
+	})
+}
+
+func TestAccFailureDataSource_condition(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+data "debug_failure" "test" {
+  condition = 1 > 2
+  message   = "One is greater than two."
+}
+`,
+			},
+			{
+				Config: `
+data "debug_failure" "test" {
+  condition = 2 > 1
+  severity  = "warning"
+  message   = "Two is greater than one."
+}
+`,
+			},
+			{
+				Config: `
+data "debug_failure" "test" {
+  condition = 2 > 1
+  message   = "Two is greater than one."
+}
+`,
+				ExpectError: regexp.MustCompile(`Two is greater than one`),
+			},
+		},
+	})
+}
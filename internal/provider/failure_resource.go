@@ -4,30 +4,78 @@
 package provider
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"unicode/utf8"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/float64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 var _ resource.Resource = &FailureResource{}
+var _ resource.ResourceWithImportState = &FailureResource{}
+var _ resource.ResourceWithModifyPlan = &FailureResource{}
+
+// failureAttemptsKey is the private state key that holds the number of
+// create and update attempts of the resource.
+const failureAttemptsKey = "attempts"
+
+// failureImportKey is the private state key that tracks whether the resource
+// was just imported, so that fail_on_import can fail the plan of the import.
+const failureImportKey = "import"
+
+// Values of the failureImportKey private state key. ImportState marks the
+// resource as imported and the read that follows it in the same plan as
+// refreshed. Any later read removes the key.
+var (
+	failureImportImported  = []byte(`"imported"`)
+	failureImportRefreshed = []byte(`"refreshed"`)
+)
 
 func NewFailureResource() resource.Resource {
 	return &FailureResource{}
 }
 
 type FailureResource struct {
+	providerData *debugProviderData
 }
 
 type FailureResourceModel struct {
-	FailOnCreate  types.Bool   `tfsdk:"fail_on_create"`
-	FailOnUpdate  types.Bool   `tfsdk:"fail_on_update"`
-	FailOnDestroy types.Bool   `tfsdk:"fail_on_destroy"`
-	Id            types.String `tfsdk:"id"`
+	FailOnCreate       types.Bool    `tfsdk:"fail_on_create"`
+	FailOnUpdate       types.Bool    `tfsdk:"fail_on_update"`
+	FailOnDestroy      types.Bool    `tfsdk:"fail_on_destroy"`
+	FailOnRead         types.Bool    `tfsdk:"fail_on_read"`
+	FailOnImport       types.Bool    `tfsdk:"fail_on_import"`
+	FailOnPlan         types.Bool    `tfsdk:"fail_on_plan"`
+	ReadErrorSummary   types.String  `tfsdk:"read_error_summary"`
+	ReadErrorDetail    types.String  `tfsdk:"read_error_detail"`
+	ImportErrorSummary types.String  `tfsdk:"import_error_summary"`
+	ImportErrorDetail  types.String  `tfsdk:"import_error_detail"`
+	PlanErrorSummary   types.String  `tfsdk:"plan_error_summary"`
+	PlanErrorDetail    types.String  `tfsdk:"plan_error_detail"`
+	FailureProbability types.Float64 `tfsdk:"failure_probability"`
+	Seed               types.Int64   `tfsdk:"seed"`
+	FailAfterN         types.Int64   `tfsdk:"fail_after_n_attempts"`
+	SucceedAfterN      types.Int64   `tfsdk:"succeed_after_n_attempts"`
+	Attempts           types.Int64   `tfsdk:"attempts"`
+	Diagnostics        types.List    `tfsdk:"diagnostics"`
+	Triggers           types.Map     `tfsdk:"triggers"`
+	Id                 types.String  `tfsdk:"id"`
 }
 
 func (r *FailureResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -64,11 +112,142 @@ func (r *FailureResource) Schema(ctx context.Context, req resource.SchemaRequest
 				Computed:            true,
 				Default:             booldefault.StaticBool(false),
 			},
+			"fail_on_read": schema.BoolAttribute{
+				MarkdownDescription: "Fail when the resource is read, such as during refresh. The value in state is used, " +
+					"so once applied, plans fail until it is set back to `false` and applied with `-refresh=false`.",
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+			},
+			"fail_on_import": schema.BoolAttribute{
+				MarkdownDescription: "Fail when the resource is imported with an `import` block. The error is raised while planning the import. " +
+					"Only `import` blocks are covered: `terraform import` always succeeds, since it does not pass the configuration to the provider.",
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+			},
+			"fail_on_plan": schema.BoolAttribute{
+				MarkdownDescription: "Fail when the resource is planned, except when it is destroyed.",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"failure_probability": schema.Float64Attribute{
+				MarkdownDescription: "Probability, between 0 and 1, that a create or update fails.",
+				Optional:            true,
+				Validators: []validator.Float64{
+					float64validator.Between(0, 1),
+				},
+			},
+			"seed": schema.Int64Attribute{
+				MarkdownDescription: "Seed used with `failure_probability` to make failures reproducible. " +
+					"Each attempt draws from the seed and the attempt number, so the same attempts fail on every run.",
+				Optional: true,
+			},
+			"fail_after_n_attempts": schema.Int64Attribute{
+				MarkdownDescription: "Number of create and update attempts that succeed before every further attempt fails.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+					int64validator.ConflictsWith(path.MatchRoot("succeed_after_n_attempts")),
+				},
+			},
+			"succeed_after_n_attempts": schema.Int64Attribute{
+				MarkdownDescription: "Number of create and update attempts that fail before every further attempt succeeds. " +
+					"A failed create leaves a tainted resource behind, and the create that replaces it continues counting from there. " +
+					"This also applies to other replacements, but not with `create_before_destroy`.",
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"attempts": schema.Int64Attribute{
+				MarkdownDescription: "Number of create and update attempts made so far, including failed ones. " +
+					"Terraform does not pass the prior state to the create of a replacement, so the count of a replaced resource is " +
+					"handed over by its destroy and only continues when the destroy runs first, in the same apply. With " +
+					"`create_before_destroy` the replacement is created first and counts from 1 again.",
+				Computed: true,
+			},
+			"diagnostics": schema.ListNestedAttribute{
+				MarkdownDescription: "Diagnostics emitted, in order, instead of the default error when a failure is triggered. " +
+					"If none of them is an error, the operation succeeds with warnings.",
+				Optional: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"severity": schema.StringAttribute{
+							MarkdownDescription: "Severity of the diagnostic. One of `error` or `warning`. Defaults to `error`.",
+							Optional:            true,
+							Validators: []validator.String{
+								stringvalidator.OneOf(failureSeverities...),
+							},
+						},
+						"summary": schema.StringAttribute{
+							MarkdownDescription: "Summary of the diagnostic.",
+							Required:            true,
+						},
+						"detail": schema.StringAttribute{
+							MarkdownDescription: "Detail of the diagnostic.",
+							Optional:            true,
+						},
+						"attribute": schema.StringAttribute{
+							MarkdownDescription: "Path of the attribute the diagnostic applies to, such as `id`, `tags[\"name\"]` or `rules[0].port`.",
+							Optional:            true,
+						},
+					},
+				},
+			},
+			"read_error_summary": schema.StringAttribute{
+				MarkdownDescription: "Summary of the error raised by `fail_on_read`. Defaults to `Read Failed`.",
+				Optional:            true,
+			},
+			"read_error_detail": schema.StringAttribute{
+				MarkdownDescription: "Detail of the error raised by `fail_on_read`.",
+				Optional:            true,
+			},
+			"import_error_summary": schema.StringAttribute{
+				MarkdownDescription: "Summary of the error raised by `fail_on_import`. Defaults to `Import Failed`.",
+				Optional:            true,
+			},
+			"import_error_detail": schema.StringAttribute{
+				MarkdownDescription: "Detail of the error raised by `fail_on_import`.",
+				Optional:            true,
+			},
+			"plan_error_summary": schema.StringAttribute{
+				MarkdownDescription: "Summary of the error raised by `fail_on_plan`. Defaults to `Plan Failed`.",
+				Optional:            true,
+			},
+			"plan_error_detail": schema.StringAttribute{
+				MarkdownDescription: "Detail of the error raised by `fail_on_plan`.",
+				Optional:            true,
+			},
+			"triggers": schema.MapAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Arbitrary values that, when changed, force the resource to be replaced, like `triggers_replace` of `terraform_data`.",
+				Optional:            true,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
 		},
 	}
 }
 
 func (r *FailureResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// The provider has not been configured yet.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*debugProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *debugProviderData, got: %T.", req.ProviderData),
+		)
+		return
+	}
+
+	r.providerData = providerData
 }
 
 func (r *FailureResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -82,16 +261,78 @@ func (r *FailureResource) Create(ctx context.Context, req resource.CreateRequest
 	}
 
 	if data.FailOnCreate.ValueBool() {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(data.failure(ctx,
 			"Create Failed",
 			"An error occurred while creating the resource.",
-		)
-		return
+		)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// Continue counting from the resource this create replaces, if any.
+	attempt := int64(1)
+	if r.providerData != nil {
+		if attempts, ok := r.providerData.failureAttempts.LoadAndDelete(data.Id.ValueString()); ok {
+			attempt += attempts.(int64)
+		}
+	}
+	data.Attempts = types.Int64Value(attempt)
+	resp.Diagnostics.Append(setFailureAttempts(ctx, resp.Private, attempt)...)
+
+	// Save the state on failure, so that Terraform taints the resource and
+	// the attempt is counted when it is replaced.
+	if data.attemptFails(ctx, attempt) {
+		resp.Diagnostics.Append(data.failure(ctx,
+			"Create Failed",
+			fmt.Sprintf("The resource failed on attempt %d.", attempt),
+		)...)
+		if resp.Diagnostics.HasError() {
+			resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+			return
+		}
 	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
+func (r *FailureResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// The resource is being destroyed.
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var data FailureResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.FailOnPlan.ValueBool() {
+		resp.Diagnostics.Append(data.failure(ctx,
+			failureMessage(data.PlanErrorSummary, "Plan Failed"),
+			failureMessage(data.PlanErrorDetail, "An error occurred while planning the resource."),
+		)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	importState, diags := req.Private.GetKey(ctx, failureImportKey)
+	resp.Diagnostics.Append(diags...)
+
+	if data.FailOnImport.ValueBool() && bytes.Equal(importState, failureImportRefreshed) {
+		resp.Diagnostics.Append(data.failure(ctx,
+			failureMessage(data.ImportErrorSummary, "Import Failed"),
+			failureMessage(data.ImportErrorDetail, "An error occurred while importing the resource."),
+		)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+}
+
 func (r *FailureResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
 	var data FailureResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
@@ -100,25 +341,77 @@ func (r *FailureResource) Read(ctx context.Context, req resource.ReadRequest, re
 		return
 	}
 
+	importState, diags := req.Private.GetKey(ctx, failureImportKey)
+	resp.Diagnostics.Append(diags...)
+
+	if bytes.Equal(importState, failureImportImported) {
+		resp.Diagnostics.Append(resp.Private.SetKey(ctx, failureImportKey, failureImportRefreshed)...)
+	} else if importState != nil {
+		resp.Diagnostics.Append(resp.Private.SetKey(ctx, failureImportKey, nil)...)
+	}
+
+	if data.FailOnRead.ValueBool() {
+		resp.Diagnostics.Append(data.failure(ctx,
+			failureMessage(data.ReadErrorSummary, "Read Failed"),
+			failureMessage(data.ReadErrorDetail, "An error occurred while reading the resource."),
+		)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
 func (r *FailureResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var data FailureResourceModel
+	var data, state FailureResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
 
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	if data.FailOnUpdate.ValueBool() {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(data.failure(ctx,
 			"Update Failed",
 			"An error occurred while updating the resource.",
-		)
+		)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	attempts, diags := getFailureAttempts(ctx, req.Private)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	attempt := attempts + 1
+	resp.Diagnostics.Append(setFailureAttempts(ctx, resp.Private, attempt)...)
+	data.Attempts = types.Int64Value(attempt)
+	state.Attempts = types.Int64Value(attempt)
+
+	// Keep the prior state on failure, so that the attempt is counted and the
+	// update is retried by the next apply.
+	if data.attemptFails(ctx, attempt) {
+		resp.Diagnostics.Append(data.failure(ctx,
+			"Update Failed",
+			fmt.Sprintf("The resource failed on attempt %d.", attempt),
+		)...)
+		if resp.Diagnostics.HasError() {
+			resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+			return
+		}
+	}
+
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
@@ -131,10 +424,121 @@ func (r *FailureResource) Delete(ctx context.Context, req resource.DeleteRequest
 	}
 
 	if data.FailOnDestroy.ValueBool() {
-		resp.Diagnostics.AddError(
+		resp.Diagnostics.Append(data.failure(ctx,
 			"Delete Failed",
 			"An error occurred while deleting the resource.",
+		)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	if r.providerData != nil && !data.Attempts.IsNull() {
+		r.providerData.failureAttempts.Store(data.Id.ValueString(), data.Attempts.ValueInt64())
+	}
+}
+
+func (r *FailureResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	if n := utf8.RuneCountInString(req.ID); n < 10 || n > 256 {
+		resp.Diagnostics.AddError(
+			"Invalid Import ID",
+			"The import ID must be between 10 and 256 characters.",
 		)
 		return
 	}
+
+	data := FailureResourceModel{
+		Id:            types.StringValue(req.ID),
+		FailOnCreate:  types.BoolValue(false),
+		FailOnUpdate:  types.BoolValue(false),
+		FailOnDestroy: types.BoolValue(false),
+		FailOnRead:    types.BoolValue(false),
+		FailOnImport:  types.BoolValue(false),
+		FailOnPlan:    types.BoolValue(false),
+		Attempts:      types.Int64Value(0),
+		Diagnostics:   types.ListNull(failureDiagnosticType),
+		Triggers:      types.MapNull(types.StringType),
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, failureImportKey, failureImportImported)...)
+}
+
+// attemptFails reports whether the given create or update attempt, counting
+// from 1, fails according to fail_after_n_attempts, succeed_after_n_attempts
+// and failure_probability.
+func (m *FailureResourceModel) attemptFails(ctx context.Context, attempt int64) bool {
+	if !m.FailAfterN.IsNull() && attempt > m.FailAfterN.ValueInt64() {
+		tflog.Info(ctx, "Failing attempt after fail_after_n_attempts", map[string]interface{}{
+			"attempt": attempt,
+		})
+		return true
+	}
+
+	if !m.SucceedAfterN.IsNull() && attempt <= m.SucceedAfterN.ValueInt64() {
+		tflog.Info(ctx, "Failing attempt before succeed_after_n_attempts", map[string]interface{}{
+			"attempt": attempt,
+		})
+		return true
+	}
+
+	if !m.FailureProbability.IsNull() {
+		draw := rand.Float64()
+		if !m.Seed.IsNull() {
+			draw = rand.New(rand.NewPCG(uint64(m.Seed.ValueInt64()), uint64(attempt))).Float64()
+		}
+
+		tflog.Info(ctx, "Drew failure probability", map[string]interface{}{
+			"attempt":             attempt,
+			"draw":                draw,
+			"failure_probability": m.FailureProbability.ValueFloat64(),
+		})
+
+		return draw < m.FailureProbability.ValueFloat64()
+	}
+
+	return false
+}
+
+// getFailureAttempts returns the number of create and update attempts
+// recorded in the private state.
+func getFailureAttempts(ctx context.Context, private failurePrivateState) (int64, diag.Diagnostics) {
+	value, diags := private.GetKey(ctx, failureAttemptsKey)
+	if diags.HasError() || value == nil {
+		return 0, diags
+	}
+
+	var attempts int64
+	if err := json.Unmarshal(value, &attempts); err != nil {
+		diags.AddError(
+			"Invalid Private State",
+			"An error occurred while reading the number of attempts from private state: "+err.Error(),
+		)
+	}
+
+	return attempts, diags
+}
+
+// setFailureAttempts records the number of create and update attempts in the
+// private state.
+func setFailureAttempts(ctx context.Context, private failurePrivateState, attempts int64) diag.Diagnostics {
+	return private.SetKey(ctx, failureAttemptsKey, []byte(strconv.FormatInt(attempts, 10)))
+}
+
+// failurePrivateState is the private state of the failure resource.
+type failurePrivateState interface {
+	GetKey(ctx context.Context, key string) ([]byte, diag.Diagnostics)
+	SetKey(ctx context.Context, key string, value []byte) diag.Diagnostics
+}
+
+// failure returns the diagnostics of a triggered failure: the configured
+// diagnostics, or an error with the given summary and detail if none are set.
+func (m *FailureResourceModel) failure(ctx context.Context, summary, detail string) diag.Diagnostics {
+	if m.Diagnostics.IsNull() || m.Diagnostics.IsUnknown() {
+		var diags diag.Diagnostics
+		diags.AddError(summary, detail)
+		return diags
+	}
+
+	return failureDiagnostics(ctx, m.Diagnostics)
 }
@@ -0,0 +1,301 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestAccFailureResource_failOnPlan(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "debug_failure" "test" {
+  id                 = "failure-test"
+  fail_on_plan       = true
+  plan_error_summary = "Custom Plan Error"
+  plan_error_detail  = "The plan was rejected."
+}
+`,
+				ExpectError: regexp.MustCompile(`Custom Plan Error`),
+			},
+		},
+	})
+}
+
+func TestAccFailureResource_failOnRead(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "debug_failure" "test" {
+  id           = "failure-test"
+  fail_on_read = true
+}
+`,
+				// The resource is created, then the refresh of the plan that
+				// follows the apply fails.
+				ExpectError: regexp.MustCompile(`Read Failed`),
+			},
+		},
+	})
+}
+
+func TestAccFailureResource_failOnImport(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "debug_failure" "test" {
+  id             = "failure-test"
+  fail_on_import = true
+}
+`,
+			},
+			{
+				ResourceName:    "debug_failure.test",
+				ImportState:     true,
+				ImportStateKind: resource.ImportBlockWithID,
+				ImportStateId:   "failure-test",
+				ExpectError:     regexp.MustCompile(`Import Failed`),
+			},
+		},
+	})
+}
+
+func TestAccFailureResource_failOnImportCommand(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "debug_failure" "test" {
+  id             = "failure-test"
+  fail_on_import = true
+}
+`,
+				// terraform import does not pass the configuration to the
+				// provider, so fail_on_import is not known and the import
+				// succeeds.
+				ResourceName:    "debug_failure.test",
+				ImportState:     true,
+				ImportStateKind: resource.ImportCommandWithID,
+				ImportStateId:   "failure-test",
+				ImportStateCheck: func(states []*terraform.InstanceState) error {
+					if len(states) != 1 || states[0].ID != "failure-test" {
+						return fmt.Errorf("expected failure-test to be imported, got: %v", states)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func TestAccFailureResource_attempts(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccFailureResourceAttemptsConfig("failure-test-1"),
+				ExpectError: regexp.MustCompile(`failed on attempt 1`),
+			},
+			{
+				// The failed create left a tainted resource, whose
+				// replacement is the second attempt.
+				Config:      testAccFailureResourceAttemptsConfig("failure-test-1"),
+				ExpectError: regexp.MustCompile(`failed on attempt 2`),
+			},
+			{
+				Config: testAccFailureResourceAttemptsConfig("failure-test-1"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"debug_failure.test",
+						tfjsonpath.New("attempts"),
+						knownvalue.Int64Exact(3),
+					),
+				},
+			},
+			{
+				// The update is the fourth attempt, which succeeds.
+				Config: testAccFailureResourceAttemptsConfig("failure-test-2"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"debug_failure.test",
+						tfjsonpath.New("attempts"),
+						knownvalue.Int64Exact(4),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestAccFailureResource_failAfterAttempts(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "debug_failure" "test" {
+  id                    = "failure-test-1"
+  fail_after_n_attempts = 1
+}
+`,
+			},
+			{
+				Config: `
+resource "debug_failure" "test" {
+  id                    = "failure-test-2"
+  fail_after_n_attempts = 1
+}
+`,
+				ExpectError: regexp.MustCompile(`failed on attempt 2`),
+			},
+		},
+	})
+}
+
+func testAccFailureResourceAttemptsConfig(id string) string {
+	return fmt.Sprintf(`
+resource "debug_failure" "test" {
+  id                       = %[1]q
+  succeed_after_n_attempts = 2
+}
+`, id)
+}
+
+func TestAccFailureResource_diagnostics(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "debug_failure" "test" {
+  id             = "failure-test"
+  fail_on_create = true
+  diagnostics = [
+    {
+      severity = "warning"
+      summary  = "Custom Warning"
+    },
+    {
+      summary   = "Custom Error"
+      detail    = "The create was rejected."
+      attribute = "id"
+    },
+  ]
+}
+`,
+				ExpectError: regexp.MustCompile(`(?s)Custom Error.*The create was rejected`),
+			},
+		},
+	})
+}
+
+func TestAccFailureResource_triggers(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccFailureResourceTriggersConfig("1"),
+			},
+			{
+				Config: testAccFailureResourceTriggersConfig("2"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("debug_failure.test", plancheck.ResourceActionReplace),
+					},
+				},
+			},
+		},
+	})
+}
+
+func testAccFailureResourceTriggersConfig(version string) string {
+	return fmt.Sprintf(`
+resource "debug_failure" "test" {
+  id = "failure-test"
+  triggers = {
+    version = %[1]q
+  }
+}
+`, version)
+}
+
+func TestAccFailureResource_replaceAttempts(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccFailureResourceReplaceConfig("1", false),
+			},
+			{
+				// The destroy runs first and hands its attempt over.
+				Config: testAccFailureResourceReplaceConfig("2", false),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"debug_failure.test",
+						tfjsonpath.New("attempts"),
+						knownvalue.Int64Exact(2),
+					),
+				},
+			},
+			{
+				// The replacement is created before the destroy, so it counts
+				// from 1 again.
+				Config: testAccFailureResourceReplaceConfig("3", true),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("debug_failure.test", plancheck.ResourceActionCreateBeforeDestroy),
+					},
+				},
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"debug_failure.test",
+						tfjsonpath.New("attempts"),
+						knownvalue.Int64Exact(1),
+					),
+				},
+			},
+		},
+	})
+}
+
+func testAccFailureResourceReplaceConfig(version string, createBeforeDestroy bool) string {
+	return fmt.Sprintf(`
+resource "debug_failure" "test" {
+  id = "failure-test"
+  triggers = {
+    version = %[1]q
+  }
+
+  lifecycle {
+    create_before_destroy = %[2]t
+  }
+}
+`, version, createBeforeDestroy)
+}
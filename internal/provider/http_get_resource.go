@@ -14,6 +14,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
@@ -25,6 +26,12 @@ import (
 )
 
 var _ resource.Resource = &HTTPGetResource{}
+var _ resource.ResourceWithImportState = &HTTPGetResource{}
+
+// defaultHTTPGetTimeout is the default timeout of the request, in seconds.
+const defaultHTTPGetTimeout = 5
+
+var httpURLRegexp = regexp.MustCompile(`^https?://`)
 
 func NewHTTPGetResource() resource.Resource {
 	return &HTTPGetResource{}
@@ -55,7 +62,7 @@ func (r *HTTPGetResource) Schema(ctx context.Context, req resource.SchemaRequest
 				MarkdownDescription: "The URL to perform the HTTP GET request on.",
 				Required:            true,
 				Validators: []validator.String{
-					stringvalidator.RegexMatches(regexp.MustCompile(`^https?://`), "must start with http:// or https://"),
+					stringvalidator.RegexMatches(httpURLRegexp, "must start with http:// or https://"),
 				},
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
@@ -76,7 +83,7 @@ func (r *HTTPGetResource) Schema(ctx context.Context, req resource.SchemaRequest
 				MarkdownDescription: "Timeout for the HTTP request in seconds. Defaults to 5 seconds if not set.",
 				Optional:            true,
 				Computed:            true,
-				Default:             int64default.StaticInt64(5),
+				Default:             int64default.StaticInt64(defaultHTTPGetTimeout),
 				Validators: []validator.Int64{
 					int64validator.Between(1, 60),
 				},
@@ -113,22 +120,84 @@ func (r *HTTPGetResource) Create(ctx context.Context, req resource.CreateRequest
 		return
 	}
 
+	resp.Diagnostics.Append(data.get(ctx)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *HTTPGetResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data HTTPGetResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *HTTPGetResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data HTTPGetResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *HTTPGetResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+}
+
+func (r *HTTPGetResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	if !httpURLRegexp.MatchString(req.ID) {
+		resp.Diagnostics.AddError(
+			"Invalid Import ID",
+			"The import ID must be a URL starting with http:// or https://.",
+		)
+		return
+	}
+
+	data := HTTPGetResourceModel{
+		URL:     types.StringValue(req.ID),
+		Headers: types.MapNull(types.StringType),
+		Timeout: types.Int64Value(defaultHTTPGetTimeout),
+	}
+
+	resp.Diagnostics.Append(data.get(ctx)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// get performs the HTTP GET request and sets the response attributes.
+func (m *HTTPGetResourceModel) get(ctx context.Context) diag.Diagnostics {
+	var diags diag.Diagnostics
+
 	httpClient := &http.Client{
-		Timeout: time.Duration(data.Timeout.ValueInt64()) * time.Second,
+		Timeout: time.Duration(m.Timeout.ValueInt64()) * time.Second,
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, data.URL.ValueString(), nil)
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, m.URL.ValueString(), nil)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		diags.AddError(
 			"HTTP Request Creation Failed",
 			"An error occurred while creating the HTTP request: "+err.Error(),
 		)
-		return
+		return diags
 	}
 
 	httpReq.Header = make(http.Header)
-	if !data.Headers.IsNull() && !data.Headers.IsUnknown() {
-		for k, v := range data.Headers.Elements() {
+	if !m.Headers.IsNull() && !m.Headers.IsUnknown() {
+		for k, v := range m.Headers.Elements() {
 			if v.IsNull() || v.IsUnknown() {
 				continue
 			}
@@ -138,15 +207,15 @@ func (r *HTTPGetResource) Create(ctx context.Context, req resource.CreateRequest
 
 	respHTTP, err := httpClient.Do(httpReq)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		diags.AddError(
 			"HTTP Request Failed",
 			"An error occurred while performing the HTTP GET request: "+err.Error(),
 		)
-		return
+		return diags
 	}
 	defer respHTTP.Body.Close()
 
-	data.ResponseStatusCode = types.Int64Value(int64(respHTTP.StatusCode))
+	m.ResponseStatusCode = types.Int64Value(int64(respHTTP.StatusCode))
 
 	headerElements := map[string]string{}
 	for k, v := range respHTTP.Header {
@@ -155,53 +224,28 @@ func (r *HTTPGetResource) Create(ctx context.Context, req resource.CreateRequest
 		}
 	}
 
-	headers, diags := types.MapValueFrom(ctx, types.StringType, headerElements)
+	headers, d := types.MapValueFrom(ctx, types.StringType, headerElements)
+	diags.Append(d...)
 	if diags.HasError() {
-		resp.Diagnostics.Append(diags...)
-		return
+		return diags
 	}
 
-	data.ResponseHeaders = headers
+	m.ResponseHeaders = headers
 
 	bodyBytes, err := io.ReadAll(respHTTP.Body)
 	if err != nil {
-		resp.Diagnostics.AddError(
+		diags.AddError(
 			"HTTP Response Read Failed",
 			"An error occurred while reading the HTTP response body: "+err.Error(),
 		)
-		return
+		return diags
 	}
 
 	if len(bodyBytes) == 0 {
-		data.ResponseBody = types.StringNull()
+		m.ResponseBody = types.StringNull()
 	} else {
-		data.ResponseBody = types.StringValue(string(bodyBytes))
-	}
-
-	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
-}
-
-func (r *HTTPGetResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
-	var data HTTPGetResourceModel
-	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
-
-	if resp.Diagnostics.HasError() {
-		return
+		m.ResponseBody = types.StringValue(string(bodyBytes))
 	}
 
-	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
-}
-
-func (r *HTTPGetResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var data HTTPGetResourceModel
-	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
-
-	if resp.Diagnostics.HasError() {
-		return
-	}
-
-	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
-}
-
-func (r *HTTPGetResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	return diags
 }
@@ -0,0 +1,59 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestAccHTTPGetResource_import(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Leave out the Date header so that the response headers do not
+		// change between requests.
+		w.Header()["Date"] = nil
+		fmt.Fprint(w, "hello")
+	}))
+	defer server.Close()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "debug_http_get" "test" {
+  url = %q
+}
+`, server.URL),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"debug_http_get.test",
+						tfjsonpath.New("response_body"),
+						knownvalue.StringExact("hello"),
+					),
+					statecheck.ExpectKnownValue(
+						"debug_http_get.test",
+						tfjsonpath.New("response_status_code"),
+						knownvalue.Int64Exact(200),
+					),
+				},
+			},
+			{
+				ResourceName:                         "debug_http_get.test",
+				ImportState:                          true,
+				ImportStateId:                        server.URL,
+				ImportStateVerify:                    true,
+				ImportStateVerifyIdentifierAttribute: "url",
+			},
+		},
+	})
+}
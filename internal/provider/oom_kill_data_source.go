@@ -6,26 +6,64 @@ package provider
 import (
 	"context"
 	"fmt"
+	"os"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 var _ datasource.DataSource = &OOMKillDataSource{}
+var _ datasource.DataSourceWithValidateConfig = &OOMKillDataSource{}
 
 func NewOOMKillDataSource() datasource.DataSource {
 	return &OOMKillDataSource{}
 }
 
 type OOMKillDataSource struct {
+	providerData *debugProviderData
 }
 
+// Ways of touching the allocated memory.
+const (
+	oomKillTouchAll  = "all"
+	oomKillTouchPage = "page"
+	oomKillTouchNone = "none"
+)
+
+var oomKillTouches = []string{oomKillTouchAll, oomKillTouchPage, oomKillTouchNone}
+
+// Profiles of memory growth over time.
+const (
+	oomKillRampLinear = "linear"
+	oomKillRampStep   = "step"
+)
+
+var oomKillRamps = []string{oomKillRampLinear, oomKillRampStep}
+
+const (
+	defaultOOMKillTouch        = oomKillTouchAll
+	defaultOOMKillRamp         = oomKillRampLinear
+	defaultOOMKillStepDuration = 10 * time.Second
+
+	// defaultOOMKillBlockDelay is the pause after each block when no
+	// allocation rate is configured.
+	defaultOOMKillBlockDelay = 100 * time.Millisecond
+)
+
 type OOMKillDataSourceModel struct {
-	Memory    types.Int64 `tfsdk:"memory"`
-	BlockSize types.Int64 `tfsdk:"block_size"`
+	Memory         types.Int64  `tfsdk:"memory"`
+	BlockSize      types.Int64  `tfsdk:"block_size"`
+	AllocationRate types.Int64  `tfsdk:"allocation_rate"`
+	Touch          types.String `tfsdk:"touch"`
+	Ramp           types.String `tfsdk:"ramp"`
+	StepDuration   types.String `tfsdk:"step_duration"`
 }
 
 func (d *OOMKillDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
@@ -46,17 +84,86 @@ func (d *OOMKillDataSource) Schema(ctx context.Context, req datasource.SchemaReq
 				MarkdownDescription: "Size of each memory block.",
 				Optional:            true,
 			},
+			"allocation_rate": schema.Int64Attribute{
+				MarkdownDescription: "Rate at which memory is allocated, in bytes per second. `0` allocates as fast as possible. " +
+					"When unset, allocation pauses for 100ms after each block.",
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"touch": schema.StringAttribute{
+				MarkdownDescription: "How the allocated memory is written. One of `all`, `page` or `none`. `all` writes every byte, " +
+					"`page` writes one byte per memory page (usually 4 KiB), which commits the memory as cheaply as possible, and " +
+					"`none` reserves the memory without writing it, so it grows virtual memory rather than RSS and relies on " +
+					"overcommit. Defaults to `all`.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(oomKillTouches...),
+				},
+			},
+			"ramp": schema.StringAttribute{
+				MarkdownDescription: "How memory grows over time. Requires `allocation_rate`. One of `linear` or `step`. `linear` " +
+					"allocates steadily at `allocation_rate`, `step` allocates `allocation_rate` times `step_duration` bytes at " +
+					"once at the start of each step. Defaults to `linear`.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(oomKillRamps...),
+					stringvalidator.AlsoRequires(path.MatchRoot("allocation_rate")),
+				},
+			},
+			"step_duration": schema.StringAttribute{
+				MarkdownDescription: "Length of each step, e.g. '30s'. Can only be set when `ramp` is `step`. Defaults to '10s'.",
+				Optional:            true,
+				Validators: []validator.String{
+					positiveDuration(),
+					stringvalidator.AlsoRequires(path.MatchRoot("ramp")),
+				},
+			},
 		},
 	}
 }
 
+func (d *OOMKillDataSource) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+	var data OOMKillDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// step_duration would be silently ignored by a linear ramp.
+	if !data.StepDuration.IsNull() && !data.Ramp.IsNull() && !data.Ramp.IsUnknown() && data.Ramp.ValueString() != oomKillRampStep {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("step_duration"),
+			"Invalid Attribute Combination",
+			fmt.Sprintf("step_duration can only be set when ramp is %q, got %q.", oomKillRampStep, data.Ramp.ValueString()),
+		)
+	}
+}
+
 func (d *OOMKillDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// The provider has not been configured yet.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*debugProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *debugProviderData, got: %T.", req.ProviderData),
+		)
+		return
+	}
 
+	d.providerData = providerData
 }
 
 func (d *OOMKillDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
 	var data OOMKillDataSourceModel
 	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	resp.Diagnostics.Append(d.providerData.checkMaxDuration(ctx, req.Config, "step_duration")...)
 
 	if resp.Diagnostics.HasError() {
 		return
@@ -84,6 +191,29 @@ func (d *OOMKillDataSource) Read(ctx context.Context, req datasource.ReadRequest
 		blockSize = 100 * 1024 * 1024 // Default to 100MB if not set or invalid
 	}
 
+	touch := defaultOOMKillTouch
+	if !data.Touch.IsNull() {
+		touch = data.Touch.ValueString()
+	}
+
+	ramp := defaultOOMKillRamp
+	if !data.Ramp.IsNull() {
+		ramp = data.Ramp.ValueString()
+	}
+
+	stepDuration := defaultOOMKillStepDuration
+	if !data.StepDuration.IsNull() {
+		var err error
+		stepDuration, err = time.ParseDuration(data.StepDuration.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Invalid Step Duration",
+				"An error occurred while parsing the step duration: "+err.Error(),
+			)
+			return
+		}
+	}
+
 	numBlocksToAllocate := int64(-1)
 	var lastBlockSize int64 = blockSize
 	if totalBytes != -1 {
@@ -107,6 +237,7 @@ func (d *OOMKillDataSource) Read(ctx context.Context, req datasource.ReadRequest
 		})
 	}
 
+	start := time.Now()
 	totalAllocatedBytes := 0
 	memoryHog := make([][]byte, 0)
 	for i := 0; ; i++ {
@@ -119,17 +250,25 @@ func (d *OOMKillDataSource) Read(ctx context.Context, req datasource.ReadRequest
 			currBlockSize = lastBlockSize
 		}
 
-		// Allocate the memory block.
+		// Allocate the memory block. Large blocks come straight from the
+		// operating system, so they are only committed once written.
 		block := make([]byte, currBlockSize)
-		for j := range block {
-			block[j] = byte(j % 256)
-		}
+		touchMemory(block, touch)
 
 		memoryHog = append(memoryHog, block)
-		time.Sleep(100 * time.Millisecond) // Simulate some delay in allocation
-
 		totalAllocatedBytes += len(block)
 
+		delay := defaultOOMKillBlockDelay
+		if !data.AllocationRate.IsNull() {
+			delay = allocationDelay(ramp, data.AllocationRate.ValueInt64(), stepDuration, int64(totalAllocatedBytes), time.Since(start))
+		}
+		if err := sleep(ctx, delay); err != nil {
+			tflog.Warn(ctx, "Memory allocation cancelled", map[string]interface{}{
+				"total_bytes": fmt.Sprintf("%d", totalAllocatedBytes),
+			})
+			break
+		}
+
 		// Log status every 1000 blocks
 		tflog.Debug(ctx, "Allocated memory block", map[string]interface{}{
 			"block_index": i,
@@ -144,3 +283,39 @@ func (d *OOMKillDataSource) Read(ctx context.Context, req datasource.ReadRequest
 		"block_size":       fmt.Sprintf("%d", blockSize),
 	})
 }
+
+// touchMemory writes to block according to touch.
+func touchMemory(block []byte, touch string) {
+	switch touch {
+	case oomKillTouchNone:
+	case oomKillTouchPage:
+		for j := 0; j < len(block); j += os.Getpagesize() {
+			block[j] = 1
+		}
+	default:
+		for j := range block {
+			block[j] = byte(j % 256)
+		}
+	}
+}
+
+// allocationDelay returns how long to wait before allocating more memory so
+// that allocated bytes, allocated over elapsed, follow the ramp at rate bytes
+// per second.
+func allocationDelay(ramp string, rate int64, step time.Duration, allocated int64, elapsed time.Duration) time.Duration {
+	if rate == 0 {
+		return 0
+	}
+
+	var next time.Duration
+	switch ramp {
+	case oomKillRampStep:
+		// Each step allows a further step worth of bytes, allocated at once.
+		stepBytes := max(int64(float64(rate)*step.Seconds()), 1)
+		next = time.Duration(allocated/stepBytes) * step
+	default:
+		next = time.Duration(float64(allocated) / float64(rate) * float64(time.Second))
+	}
+
+	return max(next-elapsed, 0)
+}
@@ -4,8 +4,10 @@
 package provider
 
 import (
+	"os"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
@@ -43,3 +45,136 @@ data "debug_oom_kill" "test" {
 	block_size = 256
 }
 `
+
+func TestAccOOMKillDataSource_allocationRate(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+data "debug_oom_kill" "test" {
+  memory = 1024
+  ramp   = "burst"
+}
+`,
+				ExpectError: regexp.MustCompile(`Invalid Attribute Value Match`),
+			},
+			{
+				Config: `
+data "debug_oom_kill" "test" {
+  memory = 1024
+  ramp   = "step"
+}
+`,
+				ExpectError: regexp.MustCompile(`Attribute "allocation_rate" must be specified when "ramp" is`),
+			},
+			{
+				Config: `
+data "debug_oom_kill" "test" {
+  memory          = 1024
+  allocation_rate = 1024
+  step_duration   = "1s"
+}
+`,
+				ExpectError: regexp.MustCompile(`Attribute "ramp" must be specified when "step_duration" is`),
+			},
+			{
+				Config: `
+data "debug_oom_kill" "test" {
+  memory          = 1024
+  allocation_rate = 1024
+  ramp            = "linear"
+  step_duration   = "1s"
+}
+`,
+				ExpectError: regexp.MustCompile(`step_duration can only be set when ramp is "step"`),
+			},
+			{
+				Config: `
+data "debug_oom_kill" "test" {
+  memory          = 4 * 1024 * 1024
+  block_size      = 1024 * 1024
+  allocation_rate = 10 * 1024 * 1024
+  touch           = "page"
+  ramp            = "step"
+  step_duration   = "200ms"
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.debug_oom_kill.test",
+						tfjsonpath.New("touch"),
+						knownvalue.StringExact("page"),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestAllocationDelay(t *testing.T) {
+	tests := []struct {
+		name      string
+		ramp      string
+		rate      int64
+		step      time.Duration
+		allocated int64
+		elapsed   time.Duration
+		want      time.Duration
+	}{
+		{name: "unlimited", ramp: oomKillRampLinear, rate: 0, allocated: 1 << 30, want: 0},
+		{name: "linear behind", ramp: oomKillRampLinear, rate: 1024, allocated: 2048, want: 2 * time.Second},
+		{name: "linear partly elapsed", ramp: oomKillRampLinear, rate: 1024, allocated: 2048, elapsed: 1500 * time.Millisecond, want: 500 * time.Millisecond},
+		{name: "linear ahead", ramp: oomKillRampLinear, rate: 1024, allocated: 2048, elapsed: 3 * time.Second, want: 0},
+		{name: "step first", ramp: oomKillRampStep, rate: 1024, step: time.Second, allocated: 0, want: 0},
+		{name: "step within first", ramp: oomKillRampStep, rate: 1024, step: time.Second, allocated: 1023, want: 0},
+		{name: "step second", ramp: oomKillRampStep, rate: 1024, step: time.Second, allocated: 1024, want: time.Second},
+		{name: "step partly elapsed", ramp: oomKillRampStep, rate: 1024, step: time.Second, allocated: 3000, elapsed: 1500 * time.Millisecond, want: 500 * time.Millisecond},
+		{name: "step ahead", ramp: oomKillRampStep, rate: 1024, step: time.Second, allocated: 3000, elapsed: 5 * time.Second, want: 0},
+		// A step smaller than a byte at the rate still allows a byte per step.
+		{name: "step below one byte", ramp: oomKillRampStep, rate: 1, step: 100 * time.Millisecond, allocated: 5, want: 500 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := allocationDelay(tt.ramp, tt.rate, tt.step, tt.allocated, tt.elapsed); got != tt.want {
+				t.Errorf("expected a delay of %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestTouchMemory(t *testing.T) {
+	pageSize := os.Getpagesize()
+	size := 3*pageSize + 10
+
+	tests := []struct {
+		touch string
+		want  int
+	}{
+		{touch: oomKillTouchNone, want: 0},
+		// The first byte of each page, including the partial last one.
+		{touch: oomKillTouchPage, want: 4},
+		// Every byte, except those written as zero every 256 bytes.
+		{touch: oomKillTouchAll, want: size - (size+255)/256},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.touch, func(t *testing.T) {
+			block := make([]byte, size)
+			touchMemory(block, tt.touch)
+
+			var touched int
+			for _, b := range block {
+				if b != 0 {
+					touched++
+				}
+			}
+
+			if touched != tt.want {
+				t.Errorf("expected %d bytes to be touched, got %d", tt.want, touched)
+			}
+		})
+	}
+}
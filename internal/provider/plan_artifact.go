@@ -0,0 +1,791 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/fs"
+	mathrand "math/rand/v2"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Content modes accepted by the plan artifact data source.
+const (
+	planArtifactContentRandom            = "random"
+	planArtifactContentZeros             = "zeros"
+	planArtifactContentRepeatingText     = "repeating_text"
+	planArtifactContentCompressibleRatio = "compressible_ratio"
+)
+
+var planArtifactContentModes = []string{
+	planArtifactContentRandom,
+	planArtifactContentZeros,
+	planArtifactContentRepeatingText,
+	planArtifactContentCompressibleRatio,
+}
+
+// Ways of allocating the space of a plan artifact.
+const (
+	planArtifactAllocationWrite     = "write"
+	planArtifactAllocationSparse    = "sparse"
+	planArtifactAllocationFallocate = "fallocate"
+)
+
+var planArtifactAllocations = []string{
+	planArtifactAllocationWrite,
+	planArtifactAllocationSparse,
+	planArtifactAllocationFallocate,
+}
+
+// Actions taken by the plan artifact resource and data source when the
+// artifact already exists.
+const (
+	planArtifactOnExistingReuse                 = "reuse"
+	planArtifactOnExistingRegenerateIfDifferent = "regenerate_if_different"
+	planArtifactOnExistingError                 = "error"
+)
+
+var planArtifactOnExistingActions = []string{
+	planArtifactOnExistingReuse,
+	planArtifactOnExistingRegenerateIfDifferent,
+	planArtifactOnExistingError,
+}
+
+// Base directories that a relative plan artifact directory is resolved
+// against.
+const (
+	planArtifactLocationWorkingDir = "working_dir"
+	planArtifactLocationDataDir    = "data_dir"
+	planArtifactLocationTempDir    = "temp_dir"
+)
+
+var planArtifactLocations = []string{
+	planArtifactLocationWorkingDir,
+	planArtifactLocationDataDir,
+	planArtifactLocationTempDir,
+}
+
+// planArtifactChunkSize is the size of the buffer used to write artifacts.
+const planArtifactChunkSize int64 = 64 * 1024 // 64 KiB
+
+const (
+	defaultPlanArtifactContentMode       = planArtifactContentRandom
+	defaultPlanArtifactAllocation        = planArtifactAllocationWrite
+	defaultPlanArtifactOnExisting        = planArtifactOnExistingReuse
+	defaultPlanArtifactLocation          = planArtifactLocationWorkingDir
+	defaultPlanArtifactContentText       = "terraform-provider-debug\n"
+	defaultPlanArtifactCompressibleRatio = 0.5
+	defaultPlanArtifactDirectoryDepth    = 0
+	defaultPlanArtifactNameLength        = 8
+)
+
+// planArtifactMarkerName is the name of the file that marks an artifact as
+// generated by the provider, so that an existing artifact is only replaced if
+// the provider generated it. It is placed in the root directory of a tree, and
+// next to a single file with the name of the file as a prefix.
+const planArtifactMarkerName = ".debug_plan_artifact"
+
+// defaultPlanArtifactResourceOnExisting is the on_existing default of the
+// resource, which removes what it generates and so does not take over an
+// existing artifact unless asked to.
+const defaultPlanArtifactResourceOnExisting = planArtifactOnExistingError
+
+// planArtifactModel holds the attributes shared by the plan artifact resource
+// and data source.
+type planArtifactModel struct {
+	FileSize          types.Int64   `tfsdk:"file_size"`
+	FileName          types.String  `tfsdk:"file_name"`
+	ContentMode       types.String  `tfsdk:"content_mode"`
+	ContentText       types.String  `tfsdk:"content_text"`
+	CompressibleRatio types.Float64 `tfsdk:"compressible_ratio"`
+	Allocation        types.String  `tfsdk:"allocation"`
+	Seed              types.Int64   `tfsdk:"seed"`
+	OnExisting        types.String  `tfsdk:"on_existing"`
+	FileCount         types.Int64   `tfsdk:"file_count"`
+	DirectoryDepth    types.Int64   `tfsdk:"directory_depth"`
+	NameLength        types.Int64   `tfsdk:"name_length"`
+	TotalSize         types.Int64   `tfsdk:"total_size"`
+	Symlinks          types.Bool    `tfsdk:"symlinks"`
+	Hardlinks         types.Bool    `tfsdk:"hardlinks"`
+	Directory         types.String  `tfsdk:"directory"`
+	Location          types.String  `tfsdk:"location"`
+	AbsolutePath      types.String  `tfsdk:"absolute_path"`
+	RootPath          types.String  `tfsdk:"root_path"`
+	GeneratedFiles    types.Int64   `tfsdk:"generated_files"`
+	Id                types.String  `tfsdk:"id"`
+}
+
+// artifactPath returns the path of the artifact. A relative file_name is
+// placed in directory, which is itself resolved against location when
+// relative.
+func (m *planArtifactModel) artifactPath() string {
+	name := m.FileName.ValueString()
+	if filepath.IsAbs(name) {
+		return name
+	}
+
+	dir := m.Directory.ValueString()
+	if !filepath.IsAbs(dir) {
+		location := defaultPlanArtifactLocation
+		if !m.Location.IsNull() {
+			location = m.Location.ValueString()
+		}
+
+		switch location {
+		case planArtifactLocationDataDir:
+			// Terraform passes its environment on to providers, so this
+			// matches the data directory used by the run.
+			dataDir := os.Getenv("TF_DATA_DIR")
+			if dataDir == "" {
+				dataDir = ".terraform"
+			}
+			dir = filepath.Join(dataDir, dir)
+		case planArtifactLocationTempDir:
+			dir = filepath.Join(os.TempDir(), dir)
+		}
+	}
+
+	return filepath.Join(dir, name)
+}
+
+// content returns the generator for the configured file content.
+func (m *planArtifactModel) content() *planArtifactContent {
+	mode := defaultPlanArtifactContentMode
+	if !m.ContentMode.IsNull() {
+		mode = m.ContentMode.ValueString()
+	}
+
+	content := newPlanArtifactContent(mode, m.Seed.ValueInt64Pointer())
+	if !m.Allocation.IsNull() {
+		content.allocation = m.Allocation.ValueString()
+	}
+	if !m.ContentText.IsNull() {
+		content.text = []byte(m.ContentText.ValueString())
+	}
+	if !m.CompressibleRatio.IsNull() {
+		content.ratio = m.CompressibleRatio.ValueFloat64()
+	}
+
+	return content
+}
+
+// tree returns the tree of files configured by file_count.
+func (m *planArtifactModel) tree() planArtifactTree {
+	tree := planArtifactTree{
+		fileCount:  m.FileCount.ValueInt64(),
+		depth:      defaultPlanArtifactDirectoryDepth,
+		nameLength: defaultPlanArtifactNameLength,
+		totalSize:  m.TotalSize.ValueInt64(),
+		symlinks:   m.Symlinks.ValueBool(),
+		hardlinks:  m.Hardlinks.ValueBool(),
+	}
+	if !m.DirectoryDepth.IsNull() {
+		tree.depth = m.DirectoryDepth.ValueInt64()
+	}
+	if !m.NameLength.IsNull() {
+		tree.nameLength = m.NameLength.ValueInt64()
+	}
+
+	return tree
+}
+
+// expected returns the number of files and the number of bytes the configured
+// artifact takes on disk.
+func (m *planArtifactModel) expected() (int64, int64) {
+	if m.FileCount.IsNull() {
+		return 1, m.FileSize.ValueInt64()
+	}
+
+	tree := m.tree()
+	files, size := tree.fileCount, tree.totalSize
+	if tree.symlinks {
+		files += tree.fileCount
+	}
+	if tree.hardlinks {
+		files += tree.fileCount
+		size *= 2
+	}
+
+	return files, size
+}
+
+// generate creates the configured artifact, or handles an existing one
+// according to on_existing, and sets the computed attributes. It returns the
+// size in bytes of the artifact on disk and whether it was generated rather
+// than reused.
+func (m *planArtifactModel) generate(ctx context.Context) (int64, bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	name := m.artifactPath()
+	onExisting := defaultPlanArtifactOnExisting
+	if !m.OnExisting.IsNull() {
+		onExisting = m.OnExisting.ValueString()
+	}
+
+	if m.FileCount.IsNull() {
+		if m.FileSize.ValueInt64() <= 0 {
+			diags.AddError(
+				"Invalid File Size",
+				"File size must be greater than zero.",
+			)
+			return 0, false, diags
+		}
+	} else if attribute, err := m.tree().validate(); err != nil {
+		diags.AddAttributeError(attribute, "Invalid File Tree", err.Error())
+		return 0, false, diags
+	}
+
+	tree := !m.FileCount.IsNull()
+	if info, err := os.Lstat(name); err != nil {
+		if !os.IsNotExist(err) {
+			diags.AddError(
+				"Unable to read existing file",
+				fmt.Sprintf("An error occurred while checking the existing file: %s", err),
+			)
+			return 0, false, diags
+		}
+		tflog.Info(ctx, fmt.Sprintf("File %s does not exist, creating new one.", name))
+	} else {
+		if onExisting == planArtifactOnExistingError {
+			diags.AddAttributeError(
+				path.Root("file_name"),
+				"Artifact Already Exists",
+				fmt.Sprintf("%s already exists. Remove it or set on_existing to reuse or regenerate it.", name),
+			)
+			return 0, false, diags
+		}
+
+		if (tree && !info.IsDir()) || (!tree && !info.Mode().IsRegular()) {
+			kind := "regular file"
+			if tree {
+				kind = "directory"
+			}
+			diags.AddAttributeError(
+				path.Root("file_name"),
+				"Artifact Kind Mismatch",
+				fmt.Sprintf("%s already exists but is not a %s, so it is neither reused nor replaced.", name, kind),
+			)
+			return 0, false, diags
+		}
+
+		size, d := m.inspect()
+		diags.Append(d...)
+		if diags.HasError() {
+			return 0, false, diags
+		}
+
+		files, expectedSize := m.expected()
+		if onExisting == planArtifactOnExistingReuse || (m.GeneratedFiles.ValueInt64() == files && size == expectedSize) {
+			tflog.Info(ctx, fmt.Sprintf("File %s already exists, using it.", name))
+			return size, false, diags
+		}
+
+		tflog.Info(ctx, fmt.Sprintf("File %s does not match the configuration, regenerating it.", name), map[string]interface{}{
+			"files":          m.GeneratedFiles.ValueInt64(),
+			"size":           size,
+			"expected_files": files,
+			"expected_size":  expectedSize,
+		})
+
+		if _, err := os.Lstat(planArtifactMarkerPath(name, tree)); err != nil {
+			diags.AddAttributeError(
+				path.Root("file_name"),
+				"Artifact Not Generated",
+				fmt.Sprintf("%s does not match the configuration, but was not generated by the provider, so it is not replaced. "+
+					"Remove it or choose another file_name.", name),
+			)
+			return 0, false, diags
+		}
+
+		if err := removePlanArtifact(name, tree); err != nil {
+			diags.AddError(
+				"Unable to remove existing file",
+				fmt.Sprintf("An error occurred while removing the existing file: %s", err),
+			)
+			return 0, false, diags
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		diags.AddError(
+			"Unable to create directory",
+			fmt.Sprintf("An error occurred while creating the directory of the artifact: %s", err),
+		)
+		return 0, false, diags
+	}
+
+	if !tree {
+		if err := writePlanArtifactFile(name, m.FileSize.ValueInt64(), m.content()); err != nil {
+			diags.AddError(
+				"Unable to write to file",
+				fmt.Sprintf("An error occurred while writing to the file: %s", err),
+			)
+			return 0, false, diags
+		}
+
+		tflog.Info(ctx, fmt.Sprintf("Wrote %d bytes to file %s", m.FileSize.ValueInt64(), name))
+	} else {
+		created, err := m.tree().generate(name, m.content())
+		if err != nil {
+			diags.AddError(
+				"Unable to generate file tree",
+				fmt.Sprintf("An error occurred while generating the file tree: %s", err),
+			)
+			return 0, false, diags
+		}
+
+		tflog.Info(ctx, fmt.Sprintf("Generated %d files below %s", created, name))
+	}
+
+	if err := os.WriteFile(planArtifactMarkerPath(name, tree), nil, 0o644); err != nil {
+		diags.AddError(
+			"Unable to mark artifact",
+			fmt.Sprintf("An error occurred while marking the artifact as generated: %s", err),
+		)
+		return 0, false, diags
+	}
+
+	size, d := m.inspect()
+	diags.Append(d...)
+	return size, true, diags
+}
+
+// inspect hashes the artifact on disk, sets the computed attributes and
+// returns its size in bytes. Sparse and fallocated artifacts read as zeros, so
+// rather than reading them in full their ID is derived from their path, size
+// and allocation.
+func (m *planArtifactModel) inspect() (int64, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	name := m.artifactPath()
+
+	allocation := defaultPlanArtifactAllocation
+	if !m.Allocation.IsNull() {
+		allocation = m.Allocation.ValueString()
+	}
+	allocated := allocation != planArtifactAllocationWrite
+
+	abs, err := filepath.Abs(name)
+	if err != nil {
+		diags.AddError(
+			"Unable to resolve path",
+			fmt.Sprintf("An error occurred while resolving the absolute path of the artifact: %s", err),
+		)
+		return 0, diags
+	}
+	m.AbsolutePath = types.StringValue(abs)
+
+	if m.FileCount.IsNull() {
+		fh, err := os.Open(name)
+		if err != nil {
+			diags.AddError(
+				"Unable to open file",
+				fmt.Sprintf("An error occurred while opening the file: %s", err),
+			)
+			return 0, diags
+		}
+		defer fh.Close()
+
+		info, err := fh.Stat()
+		if err != nil {
+			diags.AddError(
+				"Unable to read file",
+				fmt.Sprintf("An error occurred while reading the file: %s", err),
+			)
+			return 0, diags
+		}
+
+		m.RootPath = types.StringNull()
+		m.GeneratedFiles = types.Int64Value(1)
+
+		if allocated {
+			m.Id = types.StringValue(allocatedPlanArtifactID(abs, info.Size(), allocation))
+			return info.Size(), diags
+		}
+
+		hash, err := hashFile(fh)
+		if err != nil {
+			diags.AddError(
+				"Unable to hash file",
+				fmt.Sprintf("An error occurred while hashing the file: %s", err),
+			)
+			return 0, diags
+		}
+
+		m.Id = types.StringValue(hash)
+		return info.Size(), diags
+	}
+
+	hash, files, size, err := hashPlanArtifactTree(name, !allocated)
+	if err != nil {
+		diags.AddError(
+			"Unable to hash file tree",
+			fmt.Sprintf("An error occurred while hashing the file tree: %s", err),
+		)
+		return 0, diags
+	}
+
+	m.RootPath = types.StringValue(abs)
+	m.GeneratedFiles = types.Int64Value(files)
+	if allocated {
+		hash = allocatedPlanArtifactID(abs, size, allocation)
+	}
+	m.Id = types.StringValue(hash)
+	return size, diags
+}
+
+// planArtifactMarkerPath returns the path of the marker of the artifact at
+// name, which is a tree if tree is set.
+func planArtifactMarkerPath(name string, tree bool) string {
+	if tree {
+		return filepath.Join(name, planArtifactMarkerName)
+	}
+	return name + planArtifactMarkerName
+}
+
+// removePlanArtifact removes the artifact at name along with its marker.
+func removePlanArtifact(name string, tree bool) error {
+	if err := os.RemoveAll(name); err != nil {
+		return err
+	}
+	if err := os.Remove(planArtifactMarkerPath(name, tree)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// allocatedPlanArtifactID returns the ID of a sparse or fallocated artifact,
+// a sha256 hash of its path, size and allocation.
+func allocatedPlanArtifactID(abs string, size int64, allocation string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(fmt.Sprintf("%s\x00%d\x00%s", abs, size, allocation))))
+}
+
+// planArtifactContent generates the bytes written to a plan artifact.
+type planArtifactContent struct {
+	mode       string
+	text       []byte
+	ratio      float64
+	allocation string
+
+	// random is the source of random bytes.
+	random io.Reader
+
+	// offset is the number of bytes generated so far, so repeating text
+	// continues across chunks.
+	offset int64
+}
+
+// newPlanArtifactContent returns a generator for content of the given mode.
+// Random bytes come from crypto/rand unless a seed is given, in which case the
+// same seed always produces the same content.
+func newPlanArtifactContent(mode string, seed *int64) *planArtifactContent {
+	c := &planArtifactContent{
+		mode:       mode,
+		text:       []byte(defaultPlanArtifactContentText),
+		ratio:      defaultPlanArtifactCompressibleRatio,
+		allocation: defaultPlanArtifactAllocation,
+		random:     rand.Reader,
+	}
+
+	if seed != nil {
+		var key [32]byte
+		binary.LittleEndian.PutUint64(key[:], uint64(*seed))
+		c.random = mathrand.NewChaCha8(key)
+	}
+
+	return c
+}
+
+// fill overwrites chunk with the next len(chunk) bytes of content.
+func (c *planArtifactContent) fill(chunk []byte) error {
+	defer func() { c.offset += int64(len(chunk)) }()
+
+	switch c.mode {
+	case planArtifactContentZeros:
+		clear(chunk)
+	case planArtifactContentRepeatingText:
+		for i := range chunk {
+			chunk[i] = c.text[(c.offset+int64(i))%int64(len(c.text))]
+		}
+	case planArtifactContentCompressibleRatio:
+		// Zeros compress to almost nothing while random bytes do not
+		// compress at all, so the compressed size of each chunk is roughly
+		// the random part.
+		zeros := int(float64(len(chunk)) * c.ratio)
+		clear(chunk[:zeros])
+		if _, err := io.ReadFull(c.random, chunk[zeros:]); err != nil {
+			return err
+		}
+	default:
+		if _, err := io.ReadFull(c.random, chunk); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writePlanArtifact writes size bytes of content to w and returns the number
+// of bytes written.
+func writePlanArtifact(w io.Writer, size int64, content *planArtifactContent) (int64, error) {
+	chunk := make([]byte, min(size, planArtifactChunkSize))
+	var written int64
+	for written < size {
+		bytes := min(size-written, planArtifactChunkSize)
+
+		if err := content.fill(chunk[:bytes]); err != nil {
+			return written, fmt.Errorf("generating content: %w", err)
+		}
+
+		n, err := w.Write(chunk[:bytes])
+		written += int64(n)
+		if err != nil {
+			return written, err
+		}
+	}
+
+	return written, nil
+}
+
+// planArtifactTree describes a tree of generated files. Directories form a
+// single chain depth levels deep and files are spread evenly across the
+// levels, so both the number of entries and the length of their paths can be
+// controlled.
+type planArtifactTree struct {
+	fileCount  int64
+	depth      int64
+	nameLength int64
+	totalSize  int64
+	symlinks   bool
+	hardlinks  bool
+}
+
+// validate checks the dimensions of the tree, and that every entry of the
+// tree can be given a unique name of the configured length. It returns the
+// path of the attribute at fault along with the error.
+func (t planArtifactTree) validate() (path.Path, error) {
+	switch {
+	case t.fileCount < 1:
+		return path.Root("file_count"), fmt.Errorf("file_count must be at least 1, got %d", t.fileCount)
+	case t.depth < 0:
+		return path.Root("directory_depth"), fmt.Errorf("directory_depth must not be negative, got %d", t.depth)
+	case t.totalSize < 0:
+		return path.Root("total_size"), fmt.Errorf("total_size must not be negative, got %d", t.totalSize)
+	}
+
+	digits := int64(len(strconv.FormatInt(max(t.fileCount-1, t.depth), 10)))
+	if t.nameLength-1 >= digits {
+		return path.Empty(), nil
+	}
+
+	// A configured name_length is too short. With the default one, the
+	// number of files or directories is too large instead.
+	attribute := path.Root("name_length")
+	if t.nameLength == defaultPlanArtifactNameLength {
+		attribute = path.Root("file_count")
+		if t.depth > t.fileCount-1 {
+			attribute = path.Root("directory_depth")
+		}
+	}
+
+	return attribute, fmt.Errorf("name_length must be at least %d to give each of the %d files and %d directories a unique name",
+		digits+1, t.fileCount, t.depth)
+}
+
+// name returns the name of the i-th entry of a kind, identified by prefix.
+func (t planArtifactTree) name(prefix byte, i int64) string {
+	return fmt.Sprintf("%c%0*d", prefix, t.nameLength-1, i)
+}
+
+// generate creates the tree below root and returns the number of files,
+// including links, that were created.
+func (t planArtifactTree) generate(root string, content *planArtifactContent) (int64, error) {
+	dirs := make([]string, t.depth+1)
+	dirs[0] = root
+	for i := int64(1); i <= t.depth; i++ {
+		dirs[i] = filepath.Join(dirs[i-1], t.name('d', i))
+	}
+	if err := os.MkdirAll(dirs[t.depth], 0o755); err != nil {
+		return 0, err
+	}
+
+	var created int64
+	for i := int64(0); i < t.fileCount; i++ {
+		dir := dirs[i%(t.depth+1)]
+		name := t.name('f', i)
+
+		size := t.totalSize / t.fileCount
+		if i < t.totalSize%t.fileCount {
+			size++
+		}
+
+		if err := writePlanArtifactFile(filepath.Join(dir, name), size, content); err != nil {
+			return created, err
+		}
+		created++
+
+		if t.symlinks {
+			if err := os.Symlink(name, filepath.Join(dir, t.name('s', i))); err != nil {
+				return created, err
+			}
+			created++
+		}
+		if t.hardlinks {
+			if err := os.Link(filepath.Join(dir, name), filepath.Join(dir, t.name('h', i))); err != nil {
+				return created, err
+			}
+			created++
+		}
+	}
+
+	return created, nil
+}
+
+// allocatePlanArtifact gives fh a size of size bytes using the allocation of
+// content. Sparse and fallocated files read as zeros regardless of the content
+// mode, but take little or no time to create.
+func allocatePlanArtifact(fh *os.File, size int64, content *planArtifactContent) (int64, error) {
+	switch content.allocation {
+	case planArtifactAllocationSparse:
+		if err := fh.Truncate(size); err != nil {
+			return 0, err
+		}
+		return size, nil
+	case planArtifactAllocationFallocate:
+		if size == 0 {
+			return 0, nil
+		}
+		if err := fallocate(fh, size); err != nil {
+			return 0, err
+		}
+		return size, nil
+	default:
+		return writePlanArtifact(fh, size, content)
+	}
+}
+
+func writePlanArtifactFile(name string, size int64, content *planArtifactContent) error {
+	fh, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+
+	if _, err := allocatePlanArtifact(fh, size, content); err != nil {
+		return err
+	}
+	return fh.Close()
+}
+
+// hashPlanArtifactTree returns an aggregate sha256 hash of the files below
+// root, covering their paths, contents and symlink targets, along with the
+// number of files found and the combined size of the regular files. Contents
+// are only read if hashContent is set.
+func hashPlanArtifactTree(root string, hashContent bool) (string, int64, int64, error) {
+	hasher := sha256.New()
+	var files, size int64
+
+	err := filepath.WalkDir(root, func(name string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+
+		rel, err := filepath.Rel(root, name)
+		if err != nil {
+			return err
+		}
+		// The marker is not part of the generated tree.
+		if rel == planArtifactMarkerName {
+			return nil
+		}
+		fmt.Fprintf(hasher, "%s\x00", filepath.ToSlash(rel))
+		files++
+
+		if entry.Type()&fs.ModeSymlink != 0 {
+			target, err := os.Readlink(name)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(hasher, "-> %s\x00", filepath.ToSlash(target))
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+
+		if !hashContent {
+			return nil
+		}
+
+		fh, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer fh.Close()
+
+		hash, err := hashFile(fh)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(hasher, "%s\x00", hash)
+		return nil
+	})
+	if err != nil {
+		return "", 0, 0, err
+	}
+
+	return fmt.Sprintf("%x", hasher.Sum(nil)), files, size, nil
+}
+
+var _ validator.String = planArtifactFileNameValidator{}
+
+// planArtifactFileNameValidator validates that a file name names an entry of
+// its own, so that the artifact cannot resolve to its directory, the parent of
+// it or a filesystem root, which would then be overwritten or removed.
+type planArtifactFileNameValidator struct{}
+
+func (v planArtifactFileNameValidator) Description(ctx context.Context) string {
+	return "value must name a file or directory, not be empty, '.', '..' or only path separators"
+}
+
+func (v planArtifactFileNameValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v planArtifactFileNameValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	name := req.ConfigValue.ValueString()
+	switch filepath.Base(filepath.Clean(name)) {
+	case ".", "..", string(filepath.Separator):
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid File Name",
+			fmt.Sprintf("The file name must name a file or directory, got %q.", name),
+		)
+	}
+}
+
+// planArtifactFileName returns a validator that checks that a file name names
+// an entry of its own.
+func planArtifactFileName() validator.String {
+	return planArtifactFileNameValidator{}
+}
@@ -5,17 +5,20 @@ package provider
 
 import (
 	"context"
-	"crypto/rand"
 	"crypto/sha256"
 	"fmt"
 	"io"
 	"os"
-	"path/filepath"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/boolvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/float64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 var _ datasource.DataSource = &PlanArtifactDataSource{}
@@ -28,9 +31,7 @@ type PlanArtifactDataSource struct {
 }
 
 type PlanArtifactDataSourceModel struct {
-	FileSize types.Int64  `tfsdk:"file_size"`
-	FileName types.String `tfsdk:"file_name"`
-	Id       types.String `tfsdk:"id"`
+	planArtifactModel
 }
 
 func (d *PlanArtifactDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
@@ -44,17 +45,148 @@ func (d *PlanArtifactDataSource) Schema(ctx context.Context, req datasource.Sche
 
 		Attributes: map[string]schema.Attribute{
 			"file_size": schema.Int64Attribute{
-				MarkdownDescription: "Size of the artifact file in bytes.",
-				Required:            true,
+				MarkdownDescription: "Size of the artifact file in bytes. Exactly one of `file_size` or `total_size` must be set.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.ExactlyOneOf(path.MatchRoot("total_size")),
+				},
 			},
 			"file_name": schema.StringAttribute{
-				MarkdownDescription: "Name of the generated file.",
-				Required:            true,
+				MarkdownDescription: "Name of the generated file, or of the root directory when `file_count` is set. " +
+					"Relative names are placed in `directory`.",
+				Required: true,
+				Validators: []validator.String{
+					planArtifactFileName(),
+				},
 			},
-			"id": schema.StringAttribute{
-				MarkdownDescription: "Sha256 hash of the generated file.",
+			"content_mode": schema.StringAttribute{
+				MarkdownDescription: "Content written to the file. One of `random`, `zeros`, `repeating_text` or " +
+					"`compressible_ratio`. Random content does not compress, zeros compress almost entirely and " +
+					"`compressible_ratio` mixes both according to `compressible_ratio`. Defaults to `random`.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(planArtifactContentModes...),
+				},
+			},
+			"content_text": schema.StringAttribute{
+				MarkdownDescription: "Text repeated to fill the file when `content_mode` is `repeating_text`.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"compressible_ratio": schema.Float64Attribute{
+				MarkdownDescription: "Fraction of the file, between 0 and 1, that compresses away when " +
+					"`content_mode` is `compressible_ratio`. Defaults to `0.5`.",
+				Optional: true,
+				Validators: []validator.Float64{
+					float64validator.Between(0, 1),
+				},
+			},
+			"allocation": schema.StringAttribute{
+				MarkdownDescription: "How the space of each file is allocated. One of `write`, `sparse` or `fallocate`. " +
+					"`write` writes the configured content, `sparse` sets the size of the file without writing any data " +
+					"and `fallocate` reserves disk space without writing any data. Sparse and fallocated files read as " +
+					"zeros, so `content_mode` is ignored. `fallocate` is only supported on Linux. Defaults to `write`.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(planArtifactAllocations...),
+				},
+			},
+			"seed": schema.Int64Attribute{
+				MarkdownDescription: "Seed for the random content. The same seed always produces the same content, " +
+					"so the `id` is stable across runs. Content is cryptographically random when unset.",
+				Optional: true,
+			},
+			"on_existing": schema.StringAttribute{
+				MarkdownDescription: "What to do when `file_name` already exists. One of `reuse`, `regenerate_if_different` " +
+					"or `error`. `reuse` keeps the existing artifact and reports its size, `regenerate_if_different` replaces " +
+					"it when its size or number of files does not match the configuration and the provider generated it, as " +
+					"recorded by a `.debug_plan_artifact` marker next to a file or in the root of a tree. An existing entry of " +
+					"the wrong kind, such as a directory when a single file is configured, is an error. Defaults to `reuse`.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(planArtifactOnExistingActions...),
+				},
+			},
+			"file_count": schema.Int64Attribute{
+				MarkdownDescription: "Number of files to generate in a tree rooted at `file_name` instead of a single file.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+					int64validator.AlsoRequires(path.MatchRoot("total_size")),
+				},
+			},
+			"directory_depth": schema.Int64Attribute{
+				MarkdownDescription: "Number of nested directories below the root. Files are spread evenly across the levels. Defaults to `0`.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+					int64validator.AlsoRequires(path.MatchRoot("file_count")),
+				},
+			},
+			"name_length": schema.Int64Attribute{
+				MarkdownDescription: "Length of the name of each generated file and directory. Defaults to `8`.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(2),
+					int64validator.AlsoRequires(path.MatchRoot("file_count")),
+				},
+			},
+			"total_size": schema.Int64Attribute{
+				MarkdownDescription: "Combined size in bytes of the files in the tree.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+					int64validator.AlsoRequires(path.MatchRoot("file_count")),
+				},
+			},
+			"symlinks": schema.BoolAttribute{
+				MarkdownDescription: "Add a symbolic link next to each file in the tree.",
+				Optional:            true,
+				Validators: []validator.Bool{
+					boolvalidator.AlsoRequires(path.MatchRoot("file_count")),
+				},
+			},
+			"hardlinks": schema.BoolAttribute{
+				MarkdownDescription: "Add a hard link next to each file in the tree.",
+				Optional:            true,
+				Validators: []validator.Bool{
+					boolvalidator.AlsoRequires(path.MatchRoot("file_count")),
+				},
+			},
+			"directory": schema.StringAttribute{
+				MarkdownDescription: "Directory in which the artifact is created, e.g. `path.module`. A relative directory is " +
+					"resolved against `location`. Missing directories are created.",
+				Optional: true,
+			},
+			"location": schema.StringAttribute{
+				MarkdownDescription: "Base of a relative `directory`. One of `working_dir`, the directory Terraform runs in, " +
+					"`data_dir`, the `.terraform` directory or `TF_DATA_DIR` when set, or `temp_dir`, the system temporary " +
+					"directory such as `$TMPDIR`. These locations are packaged differently when the working directory is " +
+					"uploaded. Defaults to `working_dir`.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(planArtifactLocations...),
+				},
+			},
+			"absolute_path": schema.StringAttribute{
+				MarkdownDescription: "Absolute path of the generated file, or of the root directory of the tree.",
+				Computed:            true,
+			},
+			"root_path": schema.StringAttribute{
+				MarkdownDescription: "Absolute path of the root directory of the tree.",
+				Computed:            true,
+			},
+			"generated_files": schema.Int64Attribute{
+				MarkdownDescription: "Number of files generated, including links.",
 				Computed:            true,
 			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Sha256 hash of the generated file, or an aggregate hash of the paths and contents of the files in the tree. " +
+					"For `sparse` and `fallocate` allocations, a sha256 hash of the absolute path, size and allocation, so that the content is not read.",
+				Computed: true,
+			},
 		},
 	}
 }
@@ -71,118 +203,17 @@ func (d *PlanArtifactDataSource) Read(ctx context.Context, req datasource.ReadRe
 		return
 	}
 
-	if existingFile, err := os.Stat(data.FileName.ValueString()); err != nil {
-		if !os.IsNotExist(err) {
-			resp.Diagnostics.AddError(
-				"Unable to read existing file",
-				fmt.Sprintf("An error occurred while checking the existing file: %s", err),
-			)
-			return
-		}
-		tflog.Info(ctx, fmt.Sprintf("File %s does not exist, creating new one.", data.FileName.ValueString()))
-	} else {
-		tflog.Info(ctx, fmt.Sprintf("File %s already exists, using it.", existingFile.Name()))
-		data.FileSize = types.Int64Value(existingFile.Size())
-		data.FileName = types.StringValue(filepath.Base(existingFile.Name()))
-
-		fh, err := os.Open(existingFile.Name())
-		if err != nil {
-			resp.Diagnostics.AddError(
-				"Unable to open existing file",
-				fmt.Sprintf("An error occurred while opening the existing file: %s", err),
-			)
-			return
-		}
-		hash, err := hashFile(fh)
-		if err != nil {
-			resp.Diagnostics.AddError(
-				"Unable to hash existing file",
-				fmt.Sprintf("An error occurred while hashing the existing file: %s", err),
-			)
-			return
-		}
-
-		data.Id = types.StringValue(hash)
-		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
-		return
-	}
-
-	var (
-		chunkSize int64 = 64 * 1024 // 64 KiB
-		fileSize  int64 = data.FileSize.ValueInt64()
-	)
-
-	if fileSize <= 0 {
-		resp.Diagnostics.AddError(
-			"Invalid File Size",
-			"File size must be greater than zero.",
-		)
-		return
-	}
-
-	fh, err := os.Create(data.FileName.ValueString())
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Unable to create file",
-			fmt.Sprintf("An error occurred while creating the file: %s", err),
-		)
-		return
-	}
-	defer fh.Close()
-
-	chunk := make([]byte, chunkSize)
-	var written int64
-	for written < fileSize {
-		bytes := chunkSize
-		if (fileSize - written) < chunkSize {
-			bytes = fileSize - written
-		}
-
-		_, err := rand.Read(chunk[:bytes])
-		if err != nil {
-			resp.Diagnostics.AddError(
-				"Unable to generate random data",
-				fmt.Sprintf("An error occurred while generating random data: %s", err),
-			)
-			return
-		}
-
-		n, err := fh.Write(chunk[:bytes])
-		if err != nil {
-			resp.Diagnostics.AddError(
-				"Unable to write to file",
-				fmt.Sprintf("An error occurred while writing to the file: %s", err),
-			)
-			return
-		}
-
-		written += int64(n)
-	}
-
-	tflog.Info(ctx, fmt.Sprintf("Wrote %d bytes to file %s", written, fh.Name()))
-
-	data.FileSize = types.Int64Value(written)
-	data.FileName = types.StringValue(filepath.Base(fh.Name()))
-
-	// Reset file pointer to the beginning before hashing
-	if _, err := fh.Seek(0, io.SeekStart); err != nil {
-		resp.Diagnostics.AddError(
-			"Unable to seek file",
-			fmt.Sprintf("An error occurred while seeking the file before hashing: %s", err),
-		)
+	size, _, diags := data.generate(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	hash, err := hashFile(fh)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Unable to hash file",
-			fmt.Sprintf("An error occurred while hashing the file: %s", err),
-		)
-		return
+	// A reused file may differ from the configured size.
+	if data.FileCount.IsNull() {
+		data.FileSize = types.Int64Value(size)
 	}
 
-	data.Id = types.StringValue(hash)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
@@ -4,9 +4,13 @@
 package provider
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
 	"regexp"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-testing/compare"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
 	"github.com/hashicorp/terraform-plugin-testing/statecheck"
@@ -42,3 +46,369 @@ data "debug_plan_artifact" "test" {
   file_size = 1024
 }
 `
+
+func TestAccPlanArtifactDataSource_contentMode(t *testing.T) {
+	dir := t.TempDir()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+data "debug_plan_artifact" "zeros" {
+  file_name    = %q
+  file_size    = 1024
+  content_mode = "zeros"
+}
+
+data "debug_plan_artifact" "text" {
+  file_name    = %q
+  file_size    = 1024
+  content_mode = "repeating_text"
+  content_text = "ab"
+}
+`, filepath.Join(dir, "zeros"), filepath.Join(dir, "text")),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.debug_plan_artifact.zeros",
+						tfjsonpath.New("id"),
+						knownvalue.StringExact("5f70bf18a086007016e948b04aed3b82103a36bea41755b6cddfaf10ace3c6ef"),
+					),
+					statecheck.ExpectKnownValue(
+						"data.debug_plan_artifact.text",
+						tfjsonpath.New("id"),
+						knownvalue.StringExact("11eb9427bfb87a3f29e667fd98277bf568b14cdddd14ea761e319c25c558c31c"),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestAccPlanArtifactDataSource_tree(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "tree")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+data "debug_plan_artifact" "test" {
+  file_name       = %q
+  file_count      = 5
+  directory_depth = 2
+  name_length     = 4
+  total_size      = 10
+  symlinks        = true
+  hardlinks       = true
+}
+`, root),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.debug_plan_artifact.test",
+						tfjsonpath.New("root_path"),
+						knownvalue.StringExact(root),
+					),
+					statecheck.ExpectKnownValue(
+						"data.debug_plan_artifact.test",
+						tfjsonpath.New("generated_files"),
+						knownvalue.Int64Exact(15),
+					),
+					statecheck.ExpectKnownValue(
+						"data.debug_plan_artifact.test",
+						tfjsonpath.New("id"),
+						knownvalue.StringRegexp(regexp.MustCompile(`^[0-9a-f]{64}$`)),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestAccPlanArtifactDataSource_treeNameLength(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+data "debug_plan_artifact" "test" {
+  file_name   = %q
+  file_count  = 100
+  name_length = 2
+  total_size  = 0
+}
+`, filepath.Join(t.TempDir(), "tree")),
+				ExpectError: regexp.MustCompile(`name_length must be at least 3`),
+			},
+			{
+				// The default name_length is long enough, so the error is
+				// attached to the depth.
+				Config: fmt.Sprintf(`
+data "debug_plan_artifact" "test" {
+  file_name       = %q
+  file_count      = 1
+  directory_depth = 10000000
+  total_size      = 0
+}
+`, filepath.Join(t.TempDir(), "tree")),
+				ExpectError: regexp.MustCompile(`(?s)directory_depth = 10000000.*name_length must be at least 9`),
+			},
+		},
+	})
+}
+
+func TestAccPlanArtifactDataSource_seed(t *testing.T) {
+	dir := t.TempDir()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+data "debug_plan_artifact" "first" {
+  file_name = %q
+  file_size = 100000
+  seed      = 42
+}
+
+data "debug_plan_artifact" "second" {
+  file_name = %q
+  file_size = 100000
+  seed      = 42
+}
+`, filepath.Join(dir, "first"), filepath.Join(dir, "second")),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.CompareValuePairs(
+						"data.debug_plan_artifact.first",
+						tfjsonpath.New("id"),
+						"data.debug_plan_artifact.second",
+						tfjsonpath.New("id"),
+						compare.ValuesSame(),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestAccPlanArtifactDataSource_seedCompressibleRatio(t *testing.T) {
+	dir := t.TempDir()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// The seed makes the random part of the content, and so the
+				// ID, the same on every run.
+				Config: fmt.Sprintf(`
+data "debug_plan_artifact" "test" {
+  file_name          = %q
+  file_size          = 100000
+  content_mode       = "compressible_ratio"
+  compressible_ratio = 0.5
+  seed               = 42
+}
+`, filepath.Join(dir, "artifact")),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.debug_plan_artifact.test",
+						tfjsonpath.New("id"),
+						knownvalue.StringExact("0bbd29faca716705b2b91bb9d2ee3f5a2348383acecfce4e7b7c8062e2350f33"),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestAccPlanArtifactDataSource_allocation(t *testing.T) {
+	dir := t.TempDir()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+data "debug_plan_artifact" "test" {
+  file_name  = %q
+  file_size  = 1048576
+  allocation = "sparse"
+}
+`, filepath.Join(dir, "sparse")),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.debug_plan_artifact.test",
+						tfjsonpath.New("file_size"),
+						knownvalue.Int64Exact(1048576),
+					),
+					// The ID is derived from the path, size and allocation
+					// rather than from hashing the zeros the file reads as.
+					statecheck.ExpectKnownValue(
+						"data.debug_plan_artifact.test",
+						tfjsonpath.New("id"),
+						knownvalue.StringExact(allocatedPlanArtifactID(filepath.Join(dir, "sparse"), 1048576, "sparse")),
+					),
+				},
+			},
+			{
+				Config: fmt.Sprintf(`
+data "debug_plan_artifact" "test" {
+  file_name  = %q
+  file_count = 4
+  total_size = 4096
+  allocation = "sparse"
+}
+`, filepath.Join(dir, "tree")),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.debug_plan_artifact.test",
+						tfjsonpath.New("id"),
+						knownvalue.StringExact(allocatedPlanArtifactID(filepath.Join(dir, "tree"), 4096, "sparse")),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestAccPlanArtifactDataSource_onExisting(t *testing.T) {
+	dir := t.TempDir()
+	foreign := filepath.Join(dir, "foreign")
+	if err := os.WriteFile(foreign, []byte("foreign"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	generated := filepath.Join(dir, "generated")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccPlanArtifactDataSourceOnExistingConfig(foreign, 1024, "error"),
+				ExpectError: regexp.MustCompile(`Artifact Already Exists`),
+			},
+			{
+				// The file was not generated by the provider, so it is not
+				// replaced.
+				Config:      testAccPlanArtifactDataSourceOnExistingConfig(foreign, 1024, "regenerate_if_different"),
+				ExpectError: regexp.MustCompile(`Artifact Not Generated`),
+			},
+			{
+				PreConfig: func() {
+					if content, err := os.ReadFile(foreign); err != nil || string(content) != "foreign" {
+						t.Fatalf("expected %s to be left in place, got %q: %v", foreign, content, err)
+					}
+				},
+				Config: testAccPlanArtifactDataSourceOnExistingConfig(generated, 512, "regenerate_if_different"),
+			},
+			{
+				// The file generated by the previous step is replaced.
+				Config: testAccPlanArtifactDataSourceOnExistingConfig(generated, 1024, "regenerate_if_different"),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.debug_plan_artifact.test",
+						tfjsonpath.New("file_size"),
+						knownvalue.Int64Exact(1024),
+					),
+					statecheck.ExpectKnownValue(
+						"data.debug_plan_artifact.test",
+						tfjsonpath.New("id"),
+						knownvalue.StringExact("5f70bf18a086007016e948b04aed3b82103a36bea41755b6cddfaf10ace3c6ef"),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestAccPlanArtifactDataSource_onExistingForeignDirectory(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "modules")
+	foreign := filepath.Join(root, "vpc", "main.tf")
+	if err := os.MkdirAll(filepath.Dir(foreign), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(foreign, []byte("# vpc"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+data "debug_plan_artifact" "test" {
+  file_name   = %q
+  file_count  = 3
+  total_size  = 3
+  on_existing = "regenerate_if_different"
+}
+`, root),
+				ExpectError: regexp.MustCompile(`Artifact Not Generated`),
+			},
+			{
+				// The size of a sparse file is not read from its content, so
+				// the kind of the existing entry must be checked.
+				Config: fmt.Sprintf(`
+data "debug_plan_artifact" "test" {
+  file_name   = %q
+  file_size   = 1024
+  allocation  = "sparse"
+  on_existing = "regenerate_if_different"
+}
+`, root),
+				ExpectError: regexp.MustCompile(`Artifact Kind Mismatch`),
+			},
+		},
+	})
+
+	if content, err := os.ReadFile(foreign); err != nil || string(content) != "# vpc" {
+		t.Fatalf("expected %s to be left in place, got %q: %v", foreign, content, err)
+	}
+}
+
+func testAccPlanArtifactDataSourceOnExistingConfig(name string, size int64, onExisting string) string {
+	return fmt.Sprintf(`
+data "debug_plan_artifact" "test" {
+  file_name    = %q
+  file_size    = %d
+  content_mode = "zeros"
+  on_existing  = %q
+}
+`, name, size, onExisting)
+}
+
+func TestAccPlanArtifactDataSource_location(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TMPDIR", tmp)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+data "debug_plan_artifact" "test" {
+  file_name = "artifact"
+  file_size = 1024
+  directory = "nested/dir"
+  location  = "temp_dir"
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"data.debug_plan_artifact.test",
+						tfjsonpath.New("absolute_path"),
+						knownvalue.StringExact(filepath.Join(tmp, "nested", "dir", "artifact")),
+					),
+				},
+			},
+		},
+	})
+}
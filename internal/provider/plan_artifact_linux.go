@@ -0,0 +1,16 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build linux
+
+package provider
+
+import (
+	"os"
+	"syscall"
+)
+
+// fallocate reserves size bytes of disk space for fh without writing them.
+func fallocate(fh *os.File, size int64) error {
+	return syscall.Fallocate(int(fh.Fd()), 0, 0, size)
+}
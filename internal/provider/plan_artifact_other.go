@@ -0,0 +1,16 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build !linux
+
+package provider
+
+import (
+	"errors"
+	"os"
+)
+
+// fallocate is not supported on this platform.
+func fallocate(fh *os.File, size int64) error {
+	return errors.New("fallocate is only supported on Linux")
+}
@@ -0,0 +1,355 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/boolvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/float64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/float64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var _ resource.Resource = &PlanArtifactResource{}
+
+// planArtifactGeneratedKey is the private state key that marks an artifact
+// generated by the resource, as opposed to an existing one it reused, so that
+// only generated artifacts are removed on destroy.
+const planArtifactGeneratedKey = "generated"
+
+func NewPlanArtifactResource() resource.Resource {
+	return &PlanArtifactResource{}
+}
+
+type PlanArtifactResource struct {
+}
+
+type PlanArtifactResourceModel struct {
+	planArtifactModel
+}
+
+func (r *PlanArtifactResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_plan_artifact"
+}
+
+func (r *PlanArtifactResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Artifact with configurable size generated when the resource is created and removed when it is destroyed. " +
+			"Unlike the data source, the artifact does not outlive the resource, so stale artifacts from earlier runs do not " +
+			"affect later ones. If the artifact is removed outside of Terraform, it is generated again.",
+
+		Attributes: map[string]schema.Attribute{
+			"file_size": schema.Int64Attribute{
+				MarkdownDescription: "Size of the artifact file in bytes. Exactly one of `file_size` or `total_size` must be set.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.ExactlyOneOf(path.MatchRoot("total_size")),
+				},
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"file_name": schema.StringAttribute{
+				MarkdownDescription: "Name of the generated file, or of the root directory when `file_count` is set. " +
+					"Relative names are placed in `directory`.",
+				Required: true,
+				Validators: []validator.String{
+					planArtifactFileName(),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"content_mode": schema.StringAttribute{
+				MarkdownDescription: "Content written to the file. One of `random`, `zeros`, `repeating_text` or " +
+					"`compressible_ratio`. Random content does not compress, zeros compress almost entirely and " +
+					"`compressible_ratio` mixes both according to `compressible_ratio`. Defaults to `random`.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(planArtifactContentModes...),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"content_text": schema.StringAttribute{
+				MarkdownDescription: "Text repeated to fill the file when `content_mode` is `repeating_text`.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"compressible_ratio": schema.Float64Attribute{
+				MarkdownDescription: "Fraction of the file, between 0 and 1, that compresses away when " +
+					"`content_mode` is `compressible_ratio`. Defaults to `0.5`.",
+				Optional: true,
+				Validators: []validator.Float64{
+					float64validator.Between(0, 1),
+				},
+				PlanModifiers: []planmodifier.Float64{
+					float64planmodifier.RequiresReplace(),
+				},
+			},
+			"allocation": schema.StringAttribute{
+				MarkdownDescription: "How the space of each file is allocated. One of `write`, `sparse` or `fallocate`. " +
+					"`write` writes the configured content, `sparse` sets the size of the file without writing any data " +
+					"and `fallocate` reserves disk space without writing any data. Sparse and fallocated files read as " +
+					"zeros, so `content_mode` is ignored. `fallocate` is only supported on Linux. Defaults to `write`.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(planArtifactAllocations...),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"seed": schema.Int64Attribute{
+				MarkdownDescription: "Seed for the random content. The same seed always produces the same content, " +
+					"so the `id` is stable across runs. Content is cryptographically random when unset.",
+				Optional: true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"on_existing": schema.StringAttribute{
+				MarkdownDescription: "What to do when `file_name` already exists on create. One of `reuse`, " +
+					"`regenerate_if_different` or `error`. `regenerate_if_different` replaces the existing artifact when " +
+					"its size or number of files does not match the configuration and the provider generated it, as recorded " +
+					"by a `.debug_plan_artifact` marker next to a file or in the root of a tree. An existing entry of the wrong " +
+					"kind, such as a directory when a single file is configured, is an error. Only an artifact generated by " +
+					"the resource is removed on destroy, so a reused one is left in place. Defaults to `error`.",
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(defaultPlanArtifactResourceOnExisting),
+				Validators: []validator.String{
+					stringvalidator.OneOf(planArtifactOnExistingActions...),
+				},
+			},
+			"file_count": schema.Int64Attribute{
+				MarkdownDescription: "Number of files to generate in a tree rooted at `file_name` instead of a single file.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+					int64validator.AlsoRequires(path.MatchRoot("total_size")),
+				},
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"directory_depth": schema.Int64Attribute{
+				MarkdownDescription: "Number of nested directories below the root. Files are spread evenly across the levels. Defaults to `0`.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+					int64validator.AlsoRequires(path.MatchRoot("file_count")),
+				},
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"name_length": schema.Int64Attribute{
+				MarkdownDescription: "Length of the name of each generated file and directory. Defaults to `8`.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(2),
+					int64validator.AlsoRequires(path.MatchRoot("file_count")),
+				},
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"total_size": schema.Int64Attribute{
+				MarkdownDescription: "Combined size in bytes of the files in the tree.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+					int64validator.AlsoRequires(path.MatchRoot("file_count")),
+				},
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"symlinks": schema.BoolAttribute{
+				MarkdownDescription: "Add a symbolic link next to each file in the tree.",
+				Optional:            true,
+				Validators: []validator.Bool{
+					boolvalidator.AlsoRequires(path.MatchRoot("file_count")),
+				},
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			"hardlinks": schema.BoolAttribute{
+				MarkdownDescription: "Add a hard link next to each file in the tree.",
+				Optional:            true,
+				Validators: []validator.Bool{
+					boolvalidator.AlsoRequires(path.MatchRoot("file_count")),
+				},
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			"directory": schema.StringAttribute{
+				MarkdownDescription: "Directory in which the artifact is created, e.g. `path.module`. A relative directory is " +
+					"resolved against `location`. Missing directories are created.",
+				Optional: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"location": schema.StringAttribute{
+				MarkdownDescription: "Base of a relative `directory`. One of `working_dir`, the directory Terraform runs in, " +
+					"`data_dir`, the `.terraform` directory or `TF_DATA_DIR` when set, or `temp_dir`, the system temporary " +
+					"directory such as `$TMPDIR`. These locations are packaged differently when the working directory is " +
+					"uploaded. Defaults to `working_dir`.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(planArtifactLocations...),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"absolute_path": schema.StringAttribute{
+				MarkdownDescription: "Absolute path of the generated file, or of the root directory of the tree.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"root_path": schema.StringAttribute{
+				MarkdownDescription: "Absolute path of the root directory of the tree.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"generated_files": schema.Int64Attribute{
+				MarkdownDescription: "Number of files generated, including links.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Sha256 hash of the generated file, or an aggregate hash of the paths and contents of the files in the tree. " +
+					"For `sparse` and `fallocate` allocations, a sha256 hash of the absolute path, size and allocation, so that the content is not read.",
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *PlanArtifactResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+}
+
+func (r *PlanArtifactResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data PlanArtifactResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	_, generated, diags := data.generate(ctx)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if generated {
+		resp.Diagnostics.Append(resp.Private.SetKey(ctx, planArtifactGeneratedKey, []byte("true"))...)
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *PlanArtifactResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data PlanArtifactResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if _, err := os.Lstat(data.AbsolutePath.ValueString()); err != nil {
+		if !os.IsNotExist(err) {
+			resp.Diagnostics.AddError(
+				"Unable to read file",
+				fmt.Sprintf("An error occurred while checking the file: %s", err),
+			)
+			return
+		}
+
+		tflog.Warn(ctx, fmt.Sprintf("File %s no longer exists, removing it from state.", data.AbsolutePath.ValueString()))
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *PlanArtifactResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data PlanArtifactResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *PlanArtifactResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data PlanArtifactResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// An artifact that existed before the resource was created belongs to
+	// someone else.
+	generated, diags := req.Private.GetKey(ctx, planArtifactGeneratedKey)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if generated == nil {
+		tflog.Info(ctx, fmt.Sprintf("%s was not generated by the resource, leaving it in place.", data.AbsolutePath.ValueString()))
+		return
+	}
+
+	if err := removePlanArtifact(data.AbsolutePath.ValueString(), !data.FileCount.IsNull()); err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to remove file",
+			fmt.Sprintf("An error occurred while removing the file: %s", err),
+		)
+		return
+	}
+
+	tflog.Info(ctx, fmt.Sprintf("Removed %s", data.AbsolutePath.ValueString()))
+}
@@ -0,0 +1,164 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestAccPlanArtifactResource(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "artifact")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(s *terraform.State) error {
+			for _, file := range []string{name, name + planArtifactMarkerName} {
+				if _, err := os.Stat(file); !os.IsNotExist(err) {
+					return fmt.Errorf("expected %s to be removed on destroy, got: %v", file, err)
+				}
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "debug_plan_artifact" "test" {
+  file_name    = %q
+  file_size    = 1024
+  content_mode = "zeros"
+}
+`, name),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"debug_plan_artifact.test",
+						tfjsonpath.New("id"),
+						knownvalue.StringExact("5f70bf18a086007016e948b04aed3b82103a36bea41755b6cddfaf10ace3c6ef"),
+					),
+					statecheck.ExpectKnownValue(
+						"debug_plan_artifact.test",
+						tfjsonpath.New("on_existing"),
+						knownvalue.StringExact("error"),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestAccPlanArtifactResource_onExisting(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "existing")
+	if err := os.WriteFile(name, []byte("existing"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(s *terraform.State) error {
+			// The reused file was not generated by the resource, so it is
+			// left in place on destroy.
+			if _, err := os.Stat(name); err != nil {
+				return fmt.Errorf("expected %s to be left in place on destroy, got: %v", name, err)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "debug_plan_artifact" "test" {
+  file_name = %q
+  file_size = 1024
+}
+`, name),
+				ExpectError: regexp.MustCompile(`Artifact Already Exists`),
+			},
+			{
+				Config: fmt.Sprintf(`
+resource "debug_plan_artifact" "test" {
+  file_name   = %q
+  file_size   = 1024
+  on_existing = "reuse"
+}
+`, name),
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"debug_plan_artifact.test",
+						tfjsonpath.New("absolute_path"),
+						knownvalue.StringExact(name),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestAccPlanArtifactResource_invalidFileName(t *testing.T) {
+	for _, name := range []string{"", ".", "..", "a/..", "/"} {
+		t.Run(name, func(t *testing.T) {
+			resource.Test(t, resource.TestCase{
+				PreCheck:                 func() { testAccPreCheck(t) },
+				ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+				Steps: []resource.TestStep{
+					{
+						Config: fmt.Sprintf(`
+resource "debug_plan_artifact" "test" {
+  file_name  = %q
+  file_count = 1
+  total_size = 1
+}
+`, name),
+						PlanOnly:    true,
+						ExpectError: regexp.MustCompile(`Invalid File Name`),
+					},
+				},
+			})
+		})
+	}
+}
+
+func TestAccPlanArtifactResource_onExistingForeignDirectory(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "modules")
+	foreign := filepath.Join(root, "vpc", "main.tf")
+	if err := os.MkdirAll(filepath.Dir(foreign), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(foreign, []byte("# vpc"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: func(s *terraform.State) error {
+			if _, err := os.Stat(foreign); err != nil {
+				return fmt.Errorf("expected %s to be left in place, got: %v", foreign, err)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "debug_plan_artifact" "test" {
+  file_name   = %q
+  file_count  = 3
+  total_size  = 3
+  on_existing = "regenerate_if_different"
+}
+`, root),
+				ExpectError: regexp.MustCompile(`Artifact Not Generated`),
+			},
+		},
+	})
+}
@@ -0,0 +1,102 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+func TestPlanArtifactContent_compressibleRatio(t *testing.T) {
+	const size = 1 << 20
+
+	for _, ratio := range []float64{0, 0.25, 0.5, 0.9} {
+		t.Run(fmt.Sprint(ratio), func(t *testing.T) {
+			seed := int64(42)
+			content := newPlanArtifactContent(planArtifactContentCompressibleRatio, &seed)
+			content.ratio = ratio
+
+			var raw bytes.Buffer
+			if _, err := writePlanArtifact(&raw, size, content); err != nil {
+				t.Fatal(err)
+			}
+
+			var compressed bytes.Buffer
+			w := gzip.NewWriter(&compressed)
+			if _, err := w.Write(raw.Bytes()); err != nil {
+				t.Fatal(err)
+			}
+			if err := w.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			// Only the zeros compress, so the compressed size is roughly the
+			// random part of the content.
+			got := 1 - float64(compressed.Len())/float64(raw.Len())
+			if got < ratio-0.05 || got > ratio+0.05 {
+				t.Errorf("expected content to compress by %.2f, compressed by %.2f", ratio, got)
+			}
+		})
+	}
+}
+
+func TestPlanArtifactTree_validate(t *testing.T) {
+	tests := []struct {
+		name string
+		tree planArtifactTree
+		want path.Path
+	}{
+		{
+			name: "valid",
+			tree: planArtifactTree{fileCount: 100, nameLength: 3},
+			want: path.Empty(),
+		},
+		{
+			name: "no files",
+			tree: planArtifactTree{fileCount: 0, nameLength: defaultPlanArtifactNameLength},
+			want: path.Root("file_count"),
+		},
+		{
+			name: "negative depth",
+			tree: planArtifactTree{fileCount: 1, depth: -1, nameLength: defaultPlanArtifactNameLength},
+			want: path.Root("directory_depth"),
+		},
+		{
+			name: "negative total size",
+			tree: planArtifactTree{fileCount: 1, totalSize: -1, nameLength: defaultPlanArtifactNameLength},
+			want: path.Root("total_size"),
+		},
+		{
+			name: "name length too short",
+			tree: planArtifactTree{fileCount: 100, nameLength: 2},
+			want: path.Root("name_length"),
+		},
+		{
+			name: "too many files for the default name length",
+			tree: planArtifactTree{fileCount: 100000000, nameLength: defaultPlanArtifactNameLength},
+			want: path.Root("file_count"),
+		},
+		{
+			name: "too deep for the default name length",
+			tree: planArtifactTree{fileCount: 1, depth: 10000000, nameLength: defaultPlanArtifactNameLength},
+			want: path.Root("directory_depth"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.tree.validate()
+			if !got.Equal(tt.want) {
+				t.Errorf("expected the error to be attached to %q, got %q", tt.want, got)
+			}
+			if (err != nil) != !tt.want.Equal(path.Empty()) {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
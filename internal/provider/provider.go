@@ -5,13 +5,18 @@ package provider
 
 import (
 	"context"
+	"sync"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
 	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
 var _ provider.Provider = &DebugProvider{}
@@ -26,6 +31,21 @@ type DebugProvider struct {
 }
 
 type DebugProviderModel struct {
+	MaxDuration types.String `tfsdk:"max_duration"`
+}
+
+// debugProviderData is shared by the resources of a provider instance, which
+// serves a single Terraform command.
+type debugProviderData struct {
+	// maxDuration is the longest duration allowed by the duration
+	// attributes, or zero if there is no limit.
+	maxDuration time.Duration
+
+	// failureAttempts holds the attempts of destroyed failure resources by
+	// ID, so that a replacement created in the same apply continues counting.
+	// Terraform does not pass the prior state or private state to the create
+	// of a replacement, so this is the only way to hand the count over.
+	failureAttempts sync.Map
 }
 
 func (p *DebugProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -35,7 +55,17 @@ func (p *DebugProvider) Metadata(ctx context.Context, req provider.MetadataReque
 
 func (p *DebugProvider) Schema(ctx context.Context, req provider.SchemaRequest, resp *provider.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		Attributes: map[string]schema.Attribute{}}
+		Attributes: map[string]schema.Attribute{
+			"max_duration": schema.StringAttribute{
+				MarkdownDescription: "The longest duration any duration attribute of the provider's resources and data sources may be set to, such as `10m`. " +
+					"Protects shared environments from configurations that sleep or hog resources for too long. Unlimited if not set.",
+				Optional: true,
+				Validators: []validator.String{
+					positiveDuration(),
+				},
+			},
+		},
+	}
 }
 
 func (p *DebugProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
@@ -46,6 +76,24 @@ func (p *DebugProvider) Configure(ctx context.Context, req provider.ConfigureReq
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	providerData := &debugProviderData{}
+
+	if !data.MaxDuration.IsNull() && !data.MaxDuration.IsUnknown() {
+		maxDuration, err := time.ParseDuration(data.MaxDuration.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("max_duration"),
+				"Invalid Duration",
+				"Could not parse max_duration: "+err.Error(),
+			)
+			return
+		}
+		providerData.maxDuration = maxDuration
+	}
+
+	resp.ResourceData = providerData
+	resp.DataSourceData = providerData
 }
 
 func (p *DebugProvider) Resources(ctx context.Context) []func() resource.Resource {
@@ -54,6 +102,8 @@ func (p *DebugProvider) Resources(ctx context.Context) []func() resource.Resourc
 		NewSleepResource,
 		NewCommandResource,
 		NewHTTPGetResource,
+		NewCrashResource,
+		NewPlanArtifactResource,
 	}
 }
 
@@ -73,6 +123,8 @@ func (p *DebugProvider) DataSources(ctx context.Context) []func() datasource.Dat
 		NewFailureDataSource,
 		NewSystemInfoDataSource,
 		NewSleepDataSource,
+		NewCommandDataSource,
+		NewCrashDataSource,
 	}
 }
 
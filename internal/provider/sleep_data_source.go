@@ -5,10 +5,12 @@ package provider
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
@@ -20,6 +22,7 @@ func NewSleepDataSource() datasource.DataSource {
 }
 
 type SleepDataSource struct {
+	providerData *debugProviderData
 }
 
 type SleepDataSourceModel struct {
@@ -39,17 +42,36 @@ func (d *SleepDataSource) Schema(ctx context.Context, req datasource.SchemaReque
 			"duration": schema.StringAttribute{
 				MarkdownDescription: "Duration to sleep, e.g. '30s' for 30 seconds.",
 				Required:            true,
+				Validators: []validator.String{
+					positiveDuration(),
+				},
 			},
 		},
 	}
 }
 
 func (d *SleepDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// The provider has not been configured yet.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*debugProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *debugProviderData, got: %T.", req.ProviderData),
+		)
+		return
+	}
+
+	d.providerData = providerData
 }
 
 func (d *SleepDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
 	var data SleepDataSourceModel
 	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	resp.Diagnostics.Append(d.providerData.checkMaxDuration(ctx, req.Config, "duration")...)
 
 	if resp.Diagnostics.HasError() {
 		return
@@ -5,31 +5,48 @@ package provider
 
 import (
 	"context"
+	"fmt"
+	"math/rand/v2"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework-timetypes/timetypes"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 var _ resource.Resource = &SleepResource{}
+var _ resource.ResourceWithImportState = &SleepResource{}
+var _ resource.ResourceWithModifyPlan = &SleepResource{}
 
 func NewSleepResource() resource.Resource {
 	return &SleepResource{}
 }
 
 type SleepResource struct {
+	providerData *debugProviderData
 }
 
 type SleepResourceModel struct {
-	Id              timetypes.RFC3339 `tfsdk:"id"`
-	Duration        types.String      `tfsdk:"duration"`
-	UpdateDuration  types.String      `tfsdk:"update_duration"`
-	DestroyDuration types.String      `tfsdk:"destroy_duration"`
+	Id                timetypes.RFC3339 `tfsdk:"id"`
+	Duration          types.String      `tfsdk:"duration"`
+	Until             timetypes.RFC3339 `tfsdk:"until"`
+	UpdateDuration    types.String      `tfsdk:"update_duration"`
+	DestroyDuration   types.String      `tfsdk:"destroy_duration"`
+	ReadDuration      types.String      `tfsdk:"read_duration"`
+	PlanDuration      types.String      `tfsdk:"plan_duration"`
+	Jitter            types.String      `tfsdk:"jitter"`
+	HeartbeatInterval types.String      `tfsdk:"heartbeat_interval"`
+	SleptFor          types.String      `tfsdk:"slept_for"`
+	Triggers          types.Map         `tfsdk:"triggers"`
 }
 
 func (r *SleepResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -43,16 +60,65 @@ func (r *SleepResource) Schema(ctx context.Context, req resource.SchemaRequest,
 
 		Attributes: map[string]schema.Attribute{
 			"duration": schema.StringAttribute{
-				MarkdownDescription: "Duration to sleep before completing the create operation. Must be a valid duration string (e.g., '5s', '1m').",
-				Required:            true,
+				MarkdownDescription: "Duration to sleep before completing the create operation. Must be a valid duration string (e.g., '5s', '1m'). " +
+					"Exactly one of `duration` or `until` must be set.",
+				Optional: true,
+				Validators: []validator.String{
+					positiveDuration(),
+					stringvalidator.ExactlyOneOf(path.MatchRoot("until")),
+				},
+			},
+			"until": schema.StringAttribute{
+				CustomType: timetypes.RFC3339Type{},
+				MarkdownDescription: "Time until which to sleep before completing the create operation, in RFC3339 format, instead of `duration`. " +
+					"If it is in the past, the create operation does not sleep.",
+				Optional: true,
 			},
 			"update_duration": schema.StringAttribute{
 				MarkdownDescription: "Duration to sleep before completing the update operation. Must be a valid duration string (e.g., '5s', '1m').",
 				Optional:            true,
+				Validators: []validator.String{
+					positiveDuration(),
+				},
 			},
 			"destroy_duration": schema.StringAttribute{
 				MarkdownDescription: "Duration to sleep before completing the destroy operation. Must be a valid duration string (e.g., '5s', '1m').",
 				Optional:            true,
+				Validators: []validator.String{
+					positiveDuration(),
+				},
+			},
+			"read_duration": schema.StringAttribute{
+				MarkdownDescription: "Duration to sleep every time the resource is read, such as during refresh. Must be a valid duration string (e.g., '5s', '1m').",
+				Optional:            true,
+				Validators: []validator.String{
+					positiveDuration(),
+				},
+			},
+			"plan_duration": schema.StringAttribute{
+				MarkdownDescription: "Duration to sleep every time the resource is planned, except when it is destroyed. Must be a valid duration string (e.g., '5s', '1m').",
+				Optional:            true,
+				Validators: []validator.String{
+					positiveDuration(),
+				},
+			},
+			"jitter": schema.StringAttribute{
+				MarkdownDescription: "Maximum random amount of time added to or removed from the durations, e.g. '10s'.",
+				Optional:            true,
+				Validators: []validator.String{
+					positiveDuration(),
+				},
+			},
+			"heartbeat_interval": schema.StringAttribute{
+				MarkdownDescription: "Interval at which the remaining time is logged while sleeping, so that long sleeps can be told apart from hangs, e.g. '30s'.",
+				Optional:            true,
+				Validators: []validator.String{
+					positiveDuration(),
+				},
+			},
+			"slept_for": schema.StringAttribute{
+				MarkdownDescription: "Time actually slept by the last create or update operation, including when the sleep was cancelled.",
+				Computed:            true,
 			},
 			"id": schema.StringAttribute{
 				CustomType:          timetypes.RFC3339Type{},
@@ -62,11 +128,34 @@ func (r *SleepResource) Schema(ctx context.Context, req resource.SchemaRequest,
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"triggers": schema.MapAttribute{
+				ElementType:         types.StringType,
+				MarkdownDescription: "Arbitrary values that, when changed, force the resource to be replaced, like `triggers_replace` of `terraform_data`.",
+				Optional:            true,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
 		},
 	}
 }
 
 func (r *SleepResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// The provider has not been configured yet.
+	if req.ProviderData == nil {
+		return
+	}
+
+	providerData, ok := req.ProviderData.(*debugProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *debugProviderData, got: %T.", req.ProviderData),
+		)
+		return
+	}
+
+	r.providerData = providerData
 }
 
 func (r *SleepResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -79,77 +168,174 @@ func (r *SleepResource) Create(ctx context.Context, req resource.CreateRequest,
 		return
 	}
 
-	durString := data.Duration.ValueString()
-	duration, err := time.ParseDuration(durString)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Invalid Duration",
-			"Could not parse duration: "+err.Error(),
-		)
+	var duration time.Duration
+	if !data.Until.IsNull() {
+		until, diags := data.Until.ValueRFC3339Time()
+		resp.Diagnostics.Append(diags...)
+
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		duration = time.Until(until)
+	} else {
+		var diags diag.Diagnostics
+		duration, diags = data.sleepDuration(path.Root("duration"), data.Duration)
+		resp.Diagnostics.Append(diags...)
+
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	heartbeat, diags := data.heartbeatInterval()
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	tflog.Info(ctx, "Sleeping for duration", map[string]interface{}{
-		"duration": duration.String(),
-	})
+	sleptFor, err := sleepWithHeartbeat(ctx, duration, heartbeat)
+	data.SleptFor = types.StringValue(sleptFor.String())
+	data.Id = timetypes.NewRFC3339TimeValue(time.Now().UTC())
 
-	err = sleep(ctx, duration)
+	// The state is saved even if the sleep was cancelled, so that slept_for
+	// is recorded. Terraform then marks the resource as tainted.
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Sleep Failed",
-			"An error occurred while sleeping: "+err.Error(),
+			fmt.Sprintf("An error occurred after sleeping for %s: %s", sleptFor, err),
 		)
-		return
 	}
 
-	data.Id = timetypes.NewRFC3339TimeValue(time.Now().UTC())
-
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
-func (r *SleepResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
-}
+func (r *SleepResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// The resource is being destroyed.
+	if req.Plan.Raw.IsNull() {
+		return
+	}
 
-func (r *SleepResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var plan, state SleepResourceModel
+	resp.Diagnostics.Append(r.providerData.checkMaxDuration(ctx, req.Config,
+		"duration", "update_duration", "destroy_duration", "read_duration",
+		"plan_duration", "jitter", "heartbeat_interval",
+	)...)
 
-	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var data SleepResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() || data.PlanDuration.IsNull() || data.PlanDuration.IsUnknown() {
+		return
+	}
+
+	duration, diags := data.sleepDuration(path.Root("plan_duration"), data.PlanDuration)
+	resp.Diagnostics.Append(diags...)
 
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	heartbeat, diags := data.heartbeatInterval()
+	resp.Diagnostics.Append(diags...)
 
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	durString := state.UpdateDuration.ValueString()
-	if durString != "" {
-		duration, err := time.ParseDuration(durString)
-		if err != nil {
-			resp.Diagnostics.AddError(
-				"Invalid Update Duration",
-				"Could not parse update_duration: "+err.Error(),
-			)
+	sleptFor, err := sleepWithHeartbeat(ctx, duration, heartbeat)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Sleep Failed",
+			fmt.Sprintf("An error occurred after sleeping for %s: %s", sleptFor, err),
+		)
+	}
+}
+
+func (r *SleepResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data SleepResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !data.ReadDuration.IsNull() {
+		duration, diags := data.sleepDuration(path.Root("read_duration"), data.ReadDuration)
+		resp.Diagnostics.Append(diags...)
+
+		if resp.Diagnostics.HasError() {
 			return
 		}
 
-		tflog.Info(ctx, "Sleeping for duration", map[string]interface{}{
-			"duration": duration.String(),
-		})
+		heartbeat, diags := data.heartbeatInterval()
+		resp.Diagnostics.Append(diags...)
+
+		if resp.Diagnostics.HasError() {
+			return
+		}
 
-		err = sleep(ctx, duration)
+		sleptFor, err := sleepWithHeartbeat(ctx, duration, heartbeat)
 		if err != nil {
 			resp.Diagnostics.AddError(
 				"Sleep Failed",
-				"An error occurred while sleeping: "+err.Error(),
+				fmt.Sprintf("An error occurred after sleeping for %s: %s", sleptFor, err),
 			)
 			return
 		}
 	}
 
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *SleepResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state SleepResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	duration, diags := plan.sleepDuration(path.Root("update_duration"), plan.UpdateDuration)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	heartbeat, diags := plan.heartbeatInterval()
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	sleptFor, err := sleepWithHeartbeat(ctx, duration, heartbeat)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Sleep Failed",
+			fmt.Sprintf("An error occurred after sleeping for %s: %s", sleptFor, err),
+		)
+
+		state.SleptFor = types.StringValue(sleptFor.String())
+		resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+		return
+	}
+
+	plan.SleptFor = types.StringValue(sleptFor.String())
+
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
 
@@ -162,32 +348,140 @@ func (r *SleepResource) Delete(ctx context.Context, req resource.DeleteRequest,
 		return
 	}
 
-	durString := data.DestroyDuration.ValueString()
-	if durString == "" {
+	if data.DestroyDuration.IsNull() {
 		tflog.Info(ctx, "No destroy_duration specified, skipping sleep")
 		return
 	}
 
-	duration, err := time.ParseDuration(durString)
+	duration, diags := data.sleepDuration(path.Root("destroy_duration"), data.DestroyDuration)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	heartbeat, diags := data.heartbeatInterval()
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	sleptFor, err := sleepWithHeartbeat(ctx, duration, heartbeat)
 	if err != nil {
 		resp.Diagnostics.AddError(
+			"Sleep Failed",
+			fmt.Sprintf("An error occurred after sleeping for %s: %s", sleptFor, err),
+		)
+		return
+	}
+}
+
+func (r *SleepResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	id, err := time.Parse(time.RFC3339, req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid Import ID",
+			"The import ID must be a timestamp in RFC3339 format: "+err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), timetypes.NewRFC3339TimeValue(id))...)
+}
+
+// sleepDuration parses a duration attribute and applies the jitter to it. A
+// null duration is zero.
+func (m *SleepResourceModel) sleepDuration(attributePath path.Path, value types.String) (time.Duration, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if value.IsNull() {
+		return 0, diags
+	}
+
+	duration, err := time.ParseDuration(value.ValueString())
+	if err != nil {
+		diags.AddAttributeError(
+			attributePath,
 			"Invalid Duration",
 			"Could not parse duration: "+err.Error(),
 		)
-		return
+		return 0, diags
+	}
+
+	if m.Jitter.IsNull() || m.Jitter.IsUnknown() {
+		return duration, diags
+	}
+
+	jitter, err := time.ParseDuration(m.Jitter.ValueString())
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("jitter"),
+			"Invalid Jitter",
+			"Could not parse jitter: "+err.Error(),
+		)
+		return 0, diags
+	}
+
+	if jitter > 0 {
+		duration += time.Duration(rand.Int64N(int64(2*jitter)+1)) - jitter
+	}
+
+	return max(duration, 0), diags
+}
+
+// heartbeatInterval parses heartbeat_interval. A null interval is zero,
+// which disables the heartbeat.
+func (m *SleepResourceModel) heartbeatInterval() (time.Duration, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if m.HeartbeatInterval.IsNull() || m.HeartbeatInterval.IsUnknown() {
+		return 0, diags
 	}
 
+	interval, err := time.ParseDuration(m.HeartbeatInterval.ValueString())
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("heartbeat_interval"),
+			"Invalid Heartbeat Interval",
+			"Could not parse heartbeat_interval: "+err.Error(),
+		)
+	}
+
+	return interval, diags
+}
+
+// sleepWithHeartbeat sleeps for duration, logging the remaining time every
+// heartbeat if it is positive, and returns the time actually slept.
+func sleepWithHeartbeat(ctx context.Context, duration, heartbeat time.Duration) (time.Duration, error) {
 	tflog.Info(ctx, "Sleeping for duration", map[string]interface{}{
 		"duration": duration.String(),
 	})
 
-	err = sleep(ctx, duration)
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Sleep Failed",
-			"An error occurred while sleeping: "+err.Error(),
-		)
-		return
+	start := time.Now()
+	if heartbeat <= 0 {
+		err := sleep(ctx, duration)
+		return time.Since(start), err
+	}
+
+	ticker := time.NewTicker(heartbeat)
+	defer ticker.Stop()
+
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return time.Since(start), ctx.Err()
+		case <-timer.C:
+			return time.Since(start), nil
+		case <-ticker.C:
+			tflog.Info(ctx, "Still sleeping", map[string]interface{}{
+				"elapsed":   time.Since(start).Round(time.Second).String(),
+				"remaining": (duration - time.Since(start)).Round(time.Second).String(),
+			})
+		}
 	}
 }
 
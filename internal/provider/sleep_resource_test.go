@@ -0,0 +1,228 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/statecheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+func TestAccSleepResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "debug_sleep" "test" {
+  duration           = "100ms"
+  jitter             = "50ms"
+  heartbeat_interval = "10ms"
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"debug_sleep.test",
+						tfjsonpath.New("slept_for"),
+						knownvalue.StringRegexp(regexp.MustCompile(`ms$`)),
+					),
+				},
+			},
+			{
+				// The update sleeps for the planned update_duration.
+				Config: `
+resource "debug_sleep" "test" {
+  duration        = "100ms"
+  update_duration = "200ms"
+  read_duration   = "10ms"
+  plan_duration   = "10ms"
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"debug_sleep.test",
+						tfjsonpath.New("slept_for"),
+						sleptAtLeast(200*time.Millisecond),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestAccSleepResource_until(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// A deadline in the past does not sleep.
+				Config: `
+resource "debug_sleep" "test" {
+  until = "2020-01-01T00:00:00Z"
+}
+`,
+				ConfigStateChecks: []statecheck.StateCheck{
+					statecheck.ExpectKnownValue(
+						"debug_sleep.test",
+						tfjsonpath.New("slept_for"),
+						knownvalue.StringRegexp(regexp.MustCompile(`^[0-9.]+[µm]?s$`)),
+					),
+				},
+			},
+		},
+	})
+}
+
+func TestAccSleepResource_invalidDuration(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "debug_sleep" "test" {
+  duration = "5x"
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Could not parse duration`),
+			},
+			{
+				Config: `
+resource "debug_sleep" "test" {
+  duration      = "5s"
+  read_duration = "0s"
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`must be greater than zero`),
+			},
+		},
+	})
+}
+
+func TestAccSleepResource_maxDuration(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "debug" {
+  max_duration = "1s"
+}
+
+resource "debug_sleep" "test" {
+  duration      = "10ms"
+  read_duration = "1m"
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`must not exceed the provider's max_duration of 1s`),
+			},
+			{
+				Config: `
+provider "debug" {
+  max_duration = "1s"
+}
+
+data "debug_sleep" "test" {
+  duration = "2s"
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`must not exceed the provider's max_duration of 1s`),
+			},
+			{
+				Config: `
+provider "debug" {
+  max_duration = "1s"
+}
+
+resource "debug_sleep" "test" {
+  duration = "10ms"
+}
+`,
+			},
+		},
+	})
+}
+
+func TestAccSleepResource_triggers(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSleepResourceTriggersConfig("1"),
+			},
+			{
+				Config: testAccSleepResourceTriggersConfig("2"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("debug_sleep.test", plancheck.ResourceActionReplace),
+					},
+				},
+			},
+		},
+	})
+}
+
+func testAccSleepResourceTriggersConfig(version string) string {
+	return fmt.Sprintf(`
+resource "debug_sleep" "test" {
+  duration = "10ms"
+  triggers = {
+    version = %[1]q
+  }
+}
+`, version)
+}
+
+func TestAccSleepResource_import(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "debug_sleep" "test" {
+  duration = "10ms"
+}
+`,
+			},
+			{
+				ResourceName:      "debug_sleep.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+				// Only the ID is known on import.
+				ImportStateVerifyIgnore: []string{"duration", "slept_for"},
+			},
+		},
+	})
+}
+
+// sleptAtLeast checks that slept_for is a duration of at least want. There is
+// no upper bound, as a busy machine can oversleep by any amount.
+func sleptAtLeast(want time.Duration) knownvalue.Check {
+	return knownvalue.StringFunc(func(v string) error {
+		slept, err := time.ParseDuration(v)
+		if err != nil {
+			return err
+		}
+		if slept < want {
+			return fmt.Errorf("expected to sleep for at least %s, slept for %s", want, slept)
+		}
+		return nil
+	})
+}